@@ -0,0 +1,243 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"ProjectGoLive/pkg/catalog"
+	"ProjectGoLive/pkg/forms"
+	"ProjectGoLive/pkg/models"
+	"ProjectGoLive/pkg/sort"
+)
+
+// an imported catalogue can be at most maxImportSize bytes
+// and hold at most maxImportRows products.
+const (
+	maxImportSize = 4 << 20
+	maxImportRows = 2000
+)
+
+// importRow is a row of an imported catalogue along with
+// the form it was validated as.
+type importRow struct {
+	Line int
+	Form *forms.Form
+}
+
+// ProductImport creates and updates a seller's products
+// from an uploaded CSV catalogue, then redirects the
+// client to the seller home page. Every row is validated
+// like a product form first, and if any row is invalid
+// none of them are saved and the errors of each row are
+// written to the http response instead.
+func (app *application) ProductImport(w http.ResponseWriter, r *http.Request) {
+	// only a verified seller can import products
+	isSeller := app.isSeller(r)
+	verified := app.isVerified(r)
+	if !isSeller || !verified {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return
+	}
+	sellerID := app.session.GetString(r, "userid")
+
+	if r.ContentLength > maxImportSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusRequestEntityTooLarge),
+		})
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	err := r.ParseMultipartForm(maxImportSize)
+	if err != nil {
+		app.errorLog.Println(ErrInvalidForm)
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
+	form := forms.New(nil)
+	file, _, err := r.FormFile("catalogue")
+	if err != nil {
+		form.Errors.Add("catalogue", "Please choose a CSV file to import")
+		app.renderImport(w, r, sellerID, form, nil)
+		return
+	}
+	defer file.Close()
+
+	rows, err := catalog.Read(file, maxImportRows)
+	if err != nil {
+		form.Errors.Add("catalogue", fmt.Sprintf("This file cannot be imported: %v", err))
+		app.renderImport(w, r, sellerID, form, nil)
+		return
+	}
+	if len(rows) == 0 {
+		form.Errors.Add("catalogue", "This file does not have any products")
+		app.renderImport(w, r, sellerID, form, nil)
+		return
+	}
+
+	// a row with a productid updates one of the seller's
+	// products, so they are looked up to check them
+	current, err := app.products.GetSellerProducts(sellerID)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+	owned := map[int]*models.Product{}
+	for _, p := range current {
+		owned[p.ProductID] = p
+	}
+
+	// validate every row so that all of their errors are
+	// reported at once
+	products := make([]*models.Product, 0, len(rows))
+	invalid := []*importRow{}
+	seen := map[int]int{}
+	for _, row := range rows {
+		f := forms.New(row.Values)
+		validateProduct(f)
+
+		var id int
+		if v := f.Get("productid"); v != "" {
+			id, err = strconv.Atoi(v)
+			switch {
+			case err != nil || owned[id] == nil:
+				f.Errors.Add("productid", "This is not the ID of one of your products")
+			case seen[id] != 0:
+				f.Errors.Add("productid", fmt.Sprintf("This product is already updated on line %d", seen[id]))
+			default:
+				seen[id] = row.Line
+			}
+		}
+
+		if !f.Valid() {
+			invalid = append(invalid, &importRow{Line: row.Line, Form: f})
+			continue
+		}
+		p := productFromForm(f)
+		p.ProductID = id
+		products = append(products, p)
+	}
+	if len(invalid) > 0 {
+		form.Errors.Add("catalogue", fmt.Sprintf("%d of %d rows are invalid, so no products were imported", len(invalid), len(rows)))
+		app.renderImport(w, r, sellerID, form, invalid)
+		return
+	}
+
+	// the products which are updated are known before the
+	// new ones are given their ProductIDs
+	updated := []int{}
+	for _, p := range products {
+		if p.ProductID != 0 {
+			updated = append(updated, p.ProductID)
+		}
+	}
+
+	err = app.products.Import(sellerID, products)
+	if errors.Is(err, models.ErrNoRecord) {
+		form.Errors.Add("catalogue", "A product in this file has been deleted, so no products were imported")
+		app.renderImport(w, r, sellerID, form, nil)
+		return
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	// the price, discount and inventory of a product with
+	// variants are those rolled up from its variants, so
+	// the product is read back once they are
+	variants, err := app.variants.GetVariants(updated)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+	}
+	for i, p := range products {
+		if variants[p.ProductID] == nil {
+			continue
+		}
+		err = app.variants.RollUp(p.ProductID)
+		if err == nil {
+			products[i], err = app.products.Get(p.ProductID)
+		}
+		if err != nil {
+			app.errorLog.Println(ErrMySQL, err)
+			products[i] = p
+		}
+	}
+
+	// make the imported products searchable straight away
+	for _, p := range products {
+		if prev, ok := owned[p.ProductID]; ok {
+			p.UnitSold = prev.UnitSold
+			app.indSlice.UpdateProduct(p)
+			continue
+		}
+		app.indSlice.AddProduct(p)
+	}
+
+	app.session.Put(r, "flash", fmt.Sprintf("Catalogue imported: %d products added and %d updated.", len(products)-len(updated), len(updated)))
+
+	http.Redirect(w, r, "/sellerhome", http.StatusSeeOther)
+}
+
+// ProductExport writes the seller's products to the http
+// response as a CSV catalogue, which can be edited and
+// imported again.
+func (app *application) ProductExport(w http.ResponseWriter, r *http.Request) {
+	// only a seller has products to export
+	if !app.isSeller(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return
+	}
+	sellerID := app.session.GetString(r, "userid")
+
+	products, err := app.products.GetSellerProducts(sellerID)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+	sort.Sort(products, sort.MustParseSpec("id"))
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="products.csv"`)
+	err = catalog.Write(w, products)
+	if err != nil {
+		app.errorLog.Println("Error writing catalogue..", err)
+	}
+}
+
+// renderImport writes the import report, with the errors
+// of the form and of the invalid rows, to the http response.
+func (app *application) renderImport(w http.ResponseWriter, r *http.Request, sellerID string, form *forms.Form, invalid []*importRow) {
+	w.WriteHeader(http.StatusBadRequest)
+	app.render(w, r, "import.page.tmpl", &templateData{
+		Form:    form,
+		User:    &models.User{UserID: sellerID, Seller: true},
+		Imports: invalid,
+		Columns: catalog.Columns,
+	})
+}
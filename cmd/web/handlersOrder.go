@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"ProjectGoLive/pkg/models"
+	"ProjectGoLive/pkg/sort"
 )
 
 // Orders retrieves all the Orders tagged to the client's
@@ -144,6 +145,17 @@ func (app *application) SellerHome(w http.ResponseWriter, r *http.Request) {
 	}
 	sellerid := app.session.GetString(r, "userid")
 
+	// retrieve sortby or sort from URL
+	query := r.URL.Query()
+	spec, err := sortSpec(query)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
 	// retrieve product information from the database
 	products, err := app.products.GetSellerProducts(sellerid)
 	if err != nil {
@@ -151,9 +163,20 @@ func (app *application) SellerHome(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// if sortby or sort exist, sort the products according to
+	// their price under any promotion running on them
+	app.productPromotions(products...)
+	if spec != nil {
+		sort.Sort(products, spec)
+	}
+	app.productImages(products...)
+	app.productVariants(products...)
+
 	app.render(w, r, "sellerhome.page.tmpl", &templateData{
 		User:     &models.User{UserID: sellerid, Seller: isSeller},
 		Products: products,
+		Query:    query,
+		SortBy:   models.SortBy,
 	})
 }
 
@@ -174,6 +197,17 @@ func (app *application) SellerPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// retrieve sortby or sort from URL
+	query := r.URL.Query()
+	spec, err := sortSpec(query)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
 	// retrieve products tagged to the sellerid from the database
 	products, err := app.products.GetSellerProducts(sellerID)
 	if err != nil {
@@ -191,6 +225,17 @@ func (app *application) SellerPage(w http.ResponseWriter, r *http.Request) {
 		td.User = &models.User{UserID: userID, Seller: isSeller}
 	}
 
+	// if sortby or sort exist, sort the products according to
+	// their price under any promotion running on them
+	app.productPromotions(products...)
+	if spec != nil {
+		sort.Sort(products, spec)
+	}
+	app.productImages(products...)
+	app.productVariants(products...)
+
 	td.Products = products
+	td.Query = query
+	td.SortBy = models.SortBy
 	app.render(w, r, "sellerpage.page.tmpl", td)
 }
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"ProjectGoLive/pkg/forms"
@@ -38,15 +40,19 @@ func (app *application) Home(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// retrieve sortby from URL
-	// if sortby exist, sort retrieved data according to sortby
+	// retrieve sortby or sort from URL
+	// if either exist, sort retrieved data according to it
 	// else sort retrieved data according to default sortby
-	sortby := r.URL.Query().Get("sortby")
-	sortID := 0
-	for i, v := range models.SortBy {
-		if sortby == v {
-			sortID = i
-		}
+	spec, err := sortSpec(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+	if spec == nil {
+		spec = sort.Presets[models.SortBy[0]]
 	}
 
 	// retrieve product information from the database
@@ -71,29 +77,82 @@ func (app *application) Home(w http.ResponseWriter, r *http.Request) {
 		products = temp
 	}
 
-	// perform sorting on the list with the selected sort logic
-	is := sort.NewIntroSort(products, sortID)
-	is.IntroSort()
+	// the products are sorted by their price under any
+	// promotion running on them
+	app.productPromotions(products...)
+
+	// only the products up to the end of the selected page
+	// are sorted with the selected sort logic
+	query := r.URL.Query()
+	page := newPage(len(products), query)
+	products = sort.TopK(products, page.Last, spec.Less())
 
 	// prepare the templateData
-	td.Products = products
+	td.Products = products[page.First-1:]
+	app.productImages(td.Products...)
+	app.productVariants(td.Products...)
+	td.Query = query
+	td.Page = page
 	td.Categories = models.Category
 	td.SortBy = models.SortBy
 
 	app.render(w, r, "home.page.tmpl", td)
 }
 
+// snippetLength is the number of characters of a product
+// description shown in the search results.
+const snippetLength = 160
+
+// a corrected search is suggested when a search finds fewer
+// than fewResults products, if the correction has at least
+// minCorrectionConfidence.
+const (
+	fewResults              = 3
+	minCorrectionConfidence = 0.3
+)
+
 // ProductSearchResults looks up the inverted indexes for
 // to compile a list of Products which include the search
 // terms in their name, description, and keywords, retrieves
-// them from the database then writes the result to the http
-// response.
+// them from the database, filters them by the selected
+// facets then writes the result to the http response.
 func (app *application) ProductSearchResults(w http.ResponseWriter, r *http.Request) {
 	userID := app.session.GetString(r, "userid")
 	isSeller := app.isSeller(r)
 
 	// retrieve the search term from the URL
-	text := r.URL.Query().Get("text")
+	query := r.URL.Query()
+	text := query.Get("text")
+
+	// retrieve the selected facets from the URL
+	// facets which are not selected do not filter
+	filter := search.NoFilter
+	for i, v := range models.Category {
+		if query.Get("category") == v {
+			filter.CategoryID = i
+		}
+	}
+	if i, err := strconv.Atoi(query.Get("price")); err == nil && i >= 0 && i < len(search.PriceRanges) {
+		filter.PriceRange = i
+	}
+	for i, v := range models.Discount {
+		if query.Get("discount") == v {
+			filter.DiscountID = i
+		}
+	}
+	filter.SellerID = query.Get("seller")
+	filter.InStock = query.Get("instock") == "1"
+
+	// retrieve sortby or sort from URL
+	// if neither exist, results are ranked by relevance
+	spec, err := sortSpec(query)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
 
 	//
 	intArray, IDScore := app.indSlice.Search(text)
@@ -102,18 +161,97 @@ func (app *application) ProductSearchResults(w http.ResponseWriter, r *http.Requ
 	products, err := app.products.GetSearchResults(intArray)
 	if err != nil {
 		app.errorLog.Println("Error at ProductSearchResults..", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
 		return
 	}
 
+	// record the search along with the number of products
+	// found. Paging through or filtering the results keeps
+	// the qid of the search so it is only recorded once,
+	// and so a click on a result can be recorded against it.
+	// The qid is also kept in the session, so only a click
+	// on the results of the client's own search is recorded.
+	if query.Get("qid") == "" {
+		qid, err := app.analytics.RecordSearch(text, len(products))
+		if err != nil {
+			app.errorLog.Println("Error recording search..", err)
+		} else {
+			query.Set("qid", strconv.Itoa(qid))
+			app.session.Put(r, "qid", qid)
+		}
+	}
+
 	// sorts the list according to their relevance score
 	products = search.RankedProducts(products, IDScore)
 
+	// the products are faceted and sorted by their price
+	// under any promotion running on them
+	app.productPromotions(products...)
+
+	// count the facets before filtering so every facet
+	// value shows how many products selecting it leaves
+	facets := filter.Facets(products)
+	products = filter.Apply(products)
+
+	// only the products on the selected page are shown, so
+	// only those up to the end of the page are sorted with the
+	// selected sort logic
+	page := newPage(len(products), query)
+	if spec != nil {
+		products = sort.TopK(products, page.Last, spec.Less())
+	}
+	products = products[page.First-1 : page.Last]
+	app.productImages(products...)
+	app.productVariants(products...)
+
+	// show snippets of where the search terms are
+	snippets := app.indSlice.Snippets(text, products, snippetLength)
+
+	// suggest a corrected search if the search was misspelt
+	var correction *search.Correction
+	if len(intArray) < fewResults {
+		if c, ok := app.indSlice.Correct(text); ok && c.Confidence >= minCorrectionConfidence {
+			correction = &c
+		}
+	}
+
 	app.render(w, r, "searchresult.page.tmpl", &templateData{
-		Products: products,
-		User:     &models.User{UserID: userID, Seller: isSeller},
+		Products:   products,
+		User:       &models.User{UserID: userID, Seller: isSeller},
+		Facets:     facets,
+		Query:      query,
+		SortBy:     models.SortBy,
+		Page:       page,
+		Snippets:   snippets,
+		Correction: correction,
 	})
 }
 
+// ProductSuggest completes the partially typed search
+// term in the text parameter and writes the suggested
+// product names and search terms to the http response
+// as JSON.
+func (app *application) ProductSuggest(w http.ResponseWriter, r *http.Request) {
+	text := r.URL.Query().Get("text")
+
+	suggestions := app.indSlice.Complete(text, 8)
+	if suggestions == nil {
+		suggestions = []search.Suggestion{}
+	}
+
+	js, err := json.Marshal(suggestions)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(js)
+}
+
 // * CRUD operations for Product *
 
 // ProductCreate inserts a new product into the database
@@ -132,8 +270,15 @@ func (app *application) ProductCreate(w http.ResponseWriter, r *http.Request) {
 	}
 	sellerID := app.session.GetString(r, "userid")
 
-	// parse the submitted form
-	err := r.ParseForm()
+	// parse the submitted form along with its photos
+	err := parseProductForm(w, r)
+	if errors.Is(err, errFormTooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusRequestEntityTooLarge),
+		})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		app.render(w, r, "error.page.tmpl", &templateData{
@@ -144,12 +289,12 @@ func (app *application) ProductCreate(w http.ResponseWriter, r *http.Request) {
 
 	// validate the submitted form
 	form := forms.New(r.PostForm)
-	form.Required("name", "description", "keyword", "price", "category", "discount", "inventory")
-	form.MaxLength("name", 150)
-	form.MaxLength("description", 500)
-	form.MaxLength("price", 15) // should this be removed?
-	form.MatchesPattern("price", forms.PriceRX)
-	form.MaxLength("inventory", 10) // should this be removed?
+	validateProduct(form)
+	// the photos are only saved once the rest of the form is valid
+	var photos []string
+	if form.Valid() {
+		photos = app.savePhotos(r, form, maxProductImages)
+	}
 	if !form.Valid() {
 		w.WriteHeader(http.StatusBadRequest)
 		app.render(w, r, "productcreate.page.tmpl", &templateData{
@@ -181,6 +326,7 @@ func (app *application) ProductCreate(w http.ResponseWriter, r *http.Request) {
 	id, err := app.products.Create(form.Get("name"), form.Get("description"), form.Get("keyword"), sellerID, priceFloat, inventoryIDInt, catID, discID)
 	if err != nil {
 		app.errorLog.Println(ErrMySQL, err)
+		app.deletePhotos(photos)
 		w.WriteHeader(http.StatusInternalServerError)
 		app.render(w, r, "error.page.tmpl", &templateData{
 			Error: http.StatusText(http.StatusInternalServerError),
@@ -188,6 +334,16 @@ func (app *application) ProductCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// make the new product searchable straight away
+	app.indSlice.AddProduct(&models.Product{
+		ProductID: id,
+		Name:      form.Get("name"),
+		Desc:      form.Get("description"),
+		Keyword:   form.Get("keyword"),
+	})
+
+	app.addPhotos(id, photos)
+
 	app.session.Put(r, "flash", "Product successfully added!")
 
 	http.Redirect(w, r, fmt.Sprintf("/product?productid=%v", id), http.StatusSeeOther)
@@ -250,6 +406,35 @@ func (app *application) ProductRead(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	app.productImages(product)
+	app.productVariants(product)
+	app.productPromotions(product)
+
+	// show a page of the product's reviews. The product is
+	// still shown if they cannot be retrieved. The page
+	// links keep only the productid, not the search qid.
+	query := url.Values{"productid": {strconv.Itoa(id)}}
+	total, err := app.reviews.CountProductReviews(id)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+	}
+	page := newPage(total, url.Values{"page": {r.URL.Query().Get("page")}, "size": {strconv.Itoa(reviewPageSize)}})
+	var reviews []*models.Review
+	if total > 0 {
+		reviews, err = app.reviews.GetProductReviews(id, page.First-1, page.Size)
+		if err != nil {
+			app.errorLog.Println(ErrMySQL, err)
+		}
+	}
+
+	// record the click if the client came from their
+	// latest search
+	if qid, err := strconv.Atoi(r.URL.Query().Get("qid")); err == nil && qid == app.session.GetInt(r, "qid") {
+		err = app.analytics.RecordClick(qid, id)
+		if err != nil {
+			app.errorLog.Println("Error recording search click..", err)
+		}
+	}
 
 	// different options will be presented depending
 	// if the client is a seller
@@ -257,6 +442,9 @@ func (app *application) ProductRead(w http.ResponseWriter, r *http.Request) {
 		User:     &models.User{UserID: userID, Seller: isSeller},
 		Product:  product,
 		IsSeller: isSeller,
+		Reviews:  reviews,
+		Query:    query,
+		Page:     page,
 	})
 }
 
@@ -304,7 +492,15 @@ func (app *application) ProductUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = r.ParseForm()
+	// parse the submitted form along with its photos
+	err = parseProductForm(w, r)
+	if errors.Is(err, errFormTooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusRequestEntityTooLarge),
+		})
+		return
+	}
 	if err != nil {
 		app.errorLog.Println(ErrInvalidForm)
 		w.WriteHeader(http.StatusBadRequest)
@@ -314,13 +510,42 @@ func (app *application) ProductUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	p.Images, err = app.images.GetProductImages(id)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	p.Variants, err = app.variants.GetProductVariants(id)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	// the photos ticked for removal make room for new ones
+	removed := []string{}
+	for _, imageID := range p.Images {
+		for _, v := range r.PostForm["remove"] {
+			if v == imageID {
+				removed = append(removed, imageID)
+			}
+		}
+	}
+
 	form := forms.New(r.PostForm)
-	form.Required("name", "description", "keyword", "price", "category", "discount", "inventory")
-	form.MaxLength("name", 150)
-	form.MaxLength("description", 500)
-	form.MaxLength("price", 15)
-	form.MatchesPattern("price", forms.PriceRX)
-	form.MaxLength("inventory", 10)
+	validateProduct(form)
+	var photos []string
+	if form.Valid() {
+		photos = app.savePhotos(r, form, maxProductImages-len(p.Images)+len(removed))
+	}
 	if !form.Valid() {
 		app.render(w, r, "update.page.tmpl", &templateData{
 			Form:       form,
@@ -352,6 +577,7 @@ func (app *application) ProductUpdate(w http.ResponseWriter, r *http.Request) {
 	err = app.products.Update(form.Get("name"), form.Get("description"), form.Get("keyword"), priceFloat, inventory, id, catID, discID)
 	if err != nil {
 		app.errorLog.Println(ErrMySQL, err)
+		app.deletePhotos(photos)
 		w.WriteHeader(http.StatusInternalServerError)
 		app.render(w, r, "error.page.tmpl", &templateData{
 			Error: http.StatusText(http.StatusInternalServerError),
@@ -359,6 +585,34 @@ func (app *application) ProductUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the price, discount and inventory of a product with
+	// variants are those rolled up from its variants
+	if len(p.Variants) > 0 {
+		err = app.variants.RollUp(id)
+		if err != nil {
+			app.errorLog.Println(ErrMySQL, err)
+		}
+	}
+
+	// replace the product's postings in the search index
+	app.indSlice.UpdateProduct(&models.Product{
+		ProductID: id,
+		Name:      form.Get("name"),
+		Desc:      form.Get("description"),
+		Keyword:   form.Get("keyword"),
+		UnitSold:  p.UnitSold,
+	})
+
+	for _, imageID := range removed {
+		err = app.images.Delete(id, imageID)
+		if err != nil {
+			app.errorLog.Println(ErrMySQL, err)
+			continue
+		}
+		app.deletePhotos([]string{imageID})
+	}
+	app.addPhotos(id, photos)
+
 	app.session.Put(r, "flash", "Product successfully updated!")
 
 	http.Redirect(w, r, fmt.Sprintf("/product?productid=%v", id), http.StatusSeeOther)
@@ -416,6 +670,8 @@ func (app *application) ProductUpdateForm(w http.ResponseWriter, r *http.Request
 		})
 		return
 	}
+	app.productImages(product)
+	app.productVariants(product)
 
 	app.render(w, r, "update.page.tmpl", &templateData{
 		Form:       forms.New(nil),
@@ -469,6 +725,10 @@ func (app *application) ProductDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the photos are deleted along with the product's row,
+	// so find them first to delete them from the image store
+	app.productImages(p)
+
 	// perform the delete at the database
 	err = app.products.Delete(id)
 	if err != nil {
@@ -480,6 +740,10 @@ func (app *application) ProductDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// remove the product from the search index
+	app.indSlice.RemoveProduct(id)
+	app.deletePhotos(p.Images)
+
 	app.session.Put(r, "flash", "Product Successfully deleted.")
 
 	http.Redirect(w, r, "/sellerhome", http.StatusSeeOther)
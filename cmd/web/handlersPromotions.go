@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ProjectGoLive/pkg/forms"
+	"ProjectGoLive/pkg/models"
+)
+
+// promotionTime is the layout of the starts and ends
+// fields of a promotion form, as sent by a datetime-local
+// input in the server's time zone.
+const promotionTime = "2006-01-02T15:04"
+
+// ProductPromotions writes the promotions of a product,
+// with a html form for scheduling a new one, to the http
+// response.
+func (app *application) ProductPromotions(w http.ResponseWriter, r *http.Request) {
+	p, ok := app.sellerProduct(w, r)
+	if !ok {
+		return
+	}
+
+	app.renderPromotions(w, r, p, forms.New(nil))
+}
+
+// PromotionCreate schedules a new promotion of a product
+// then redirects the client back to the promotions of the
+// product.
+func (app *application) PromotionCreate(w http.ResponseWriter, r *http.Request) {
+	p, ok := app.sellerProduct(w, r)
+	if !ok {
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		app.errorLog.Println(ErrInvalidForm)
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
+	form := forms.New(r.PostForm)
+	form.Required("name", "kind", "value", "starts", "ends")
+	form.MaxLength("name", 50)
+	form.PermittedValues("kind", models.PromotionKind...)
+	form.MaxLength("value", 15)
+	form.MatchesPattern("value", forms.PriceRX)
+	form.MaxLength("stockcap", 10)
+
+	kind := models.PromotionPercent
+	if form.Get("kind") == models.PromotionKind[models.PromotionAmount] {
+		kind = models.PromotionAmount
+	}
+	value, err := strconv.ParseFloat(form.Get("value"), 64)
+	if form.Get("value") != "" {
+		switch {
+		case err != nil || value <= 0:
+			form.Errors.Add("value", "This field must be more than 0")
+		case kind == models.PromotionPercent && value > 100:
+			form.Errors.Add("value", "This field must be at most 100 percent")
+		case kind == models.PromotionAmount:
+			// an amount off must leave every variant
+			// with a price
+			lowest, ok := app.lowestPrice(w, r, p)
+			if !ok {
+				return
+			}
+			if value >= lowest {
+				form.Errors.Add("value", fmt.Sprintf("This field must be less than the lowest price of the product, $%.2f", lowest))
+			}
+		}
+	}
+	starts, err1 := time.ParseInLocation(promotionTime, form.Get("starts"), time.Local)
+	if form.Get("starts") != "" && err1 != nil {
+		form.Errors.Add("starts", "This field is invalid")
+	}
+	ends, err2 := time.ParseInLocation(promotionTime, form.Get("ends"), time.Local)
+	if form.Get("ends") != "" && err2 != nil {
+		form.Errors.Add("ends", "This field is invalid")
+	}
+	if err1 == nil && err2 == nil && !ends.After(starts) {
+		form.Errors.Add("ends", "The promotion must end after it starts")
+	} else if err2 == nil && !ends.After(time.Now()) {
+		form.Errors.Add("ends", "The promotion must end in the future")
+	}
+	stockCap := 0
+	if v := form.Get("stockcap"); v != "" {
+		stockCap, err = strconv.Atoi(v)
+		if err != nil || stockCap < 0 {
+			form.Errors.Add("stockcap", "This field must be a whole number")
+		}
+	}
+	if !form.Valid() {
+		w.WriteHeader(http.StatusBadRequest)
+		app.renderPromotions(w, r, p, form)
+		return
+	}
+
+	_, err = app.promotions.Create(p.ProductID, form.Get("name"), kind, value, starts, ends, stockCap)
+	if errors.Is(err, models.ErrOverlapping) {
+		form.Errors.Add("starts", "This product already has a promotion during this time")
+		w.WriteHeader(http.StatusBadRequest)
+		app.renderPromotions(w, r, p, form)
+		return
+	}
+	if errors.Is(err, models.ErrNoRecord) {
+		w.WriteHeader(http.StatusNotFound)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusNotFound),
+		})
+		return
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.session.Put(r, "flash", "Promotion successfully scheduled!")
+
+	http.Redirect(w, r, fmt.Sprintf("/product/promotions?productid=%v", p.ProductID), http.StatusSeeOther)
+}
+
+// PromotionDelete deletes a promotion of a product, which
+// ends it if it is running, then redirects the client back
+// to the promotions of the product.
+func (app *application) PromotionDelete(w http.ResponseWriter, r *http.Request) {
+	p, ok := app.sellerProduct(w, r)
+	if !ok {
+		return
+	}
+
+	// the promotionid form value should be valid
+	err := r.ParseForm()
+	var promotionID int
+	if err == nil {
+		promotionID, err = strconv.Atoi(r.PostForm.Get("promotionid"))
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
+	err = app.promotions.Delete(p.ProductID, promotionID)
+	if errors.Is(err, models.ErrNoRowsAffected) {
+		w.WriteHeader(http.StatusNotFound)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusNotFound),
+		})
+		return
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.session.Put(r, "flash", "Promotion successfully deleted.")
+
+	http.Redirect(w, r, fmt.Sprintf("/product/promotions?productid=%v", p.ProductID), http.StatusSeeOther)
+}
+
+// lowestPrice returns the lowest price of the product or
+// any of its variants. It writes an error to the http
+// response if the variants cannot be retrieved.
+func (app *application) lowestPrice(w http.ResponseWriter, r *http.Request, p *models.Product) (float64, bool) {
+	variants, err := app.variants.GetProductVariants(p.ProductID)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return 0, false
+	}
+
+	lowest := p.Price
+	for _, v := range variants {
+		if v.Price < lowest {
+			lowest = v.Price
+		}
+	}
+	return lowest, true
+}
+
+// renderPromotions writes the promotions page of the
+// product with the form to the http response.
+func (app *application) renderPromotions(w http.ResponseWriter, r *http.Request, p *models.Product, form *forms.Form) {
+	promotions, err := app.promotions.GetProductPromotions(p.ProductID)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.render(w, r, "promotions.page.tmpl", &templateData{
+		Form:       form,
+		User:       &models.User{UserID: p.SellerID, Seller: true},
+		Product:    p,
+		Promotions: promotions,
+		Kinds:      models.PromotionKind,
+	})
+}
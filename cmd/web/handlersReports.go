@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// number of days the search report covers when the
+// days parameter is missing, and the most queries
+// shown in each list of the report
+const (
+	defaultReportDays = 7
+	reportLimit       = 20
+)
+
+// searchReport holds the lists of queries shown on
+// the search report.
+type searchReport struct {
+	Days        int
+	Top         []*models.QueryStat
+	ZeroResults []*models.QueryStat
+	Trending    []*models.QueryStat
+}
+
+// SearchReport shows the queries buyers search for most,
+// the queries which found no products and the queries
+// trending this week, so sellers can decide what to stock
+// and which keywords to give their products.
+func (app *application) SearchReport(w http.ResponseWriter, r *http.Request) {
+	// only sellers and admins can see the report
+	isSeller := app.isSeller(r)
+	if !isSeller && !app.isAdmin(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return
+	}
+	userID := app.session.GetString(r, "userid")
+
+	// days parameter value should be valid if it exists
+	days := defaultReportDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		var err error
+		days, err = strconv.Atoi(v)
+		if err != nil || days < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			app.render(w, r, "error.page.tmpl", &templateData{
+				Error: http.StatusText(http.StatusBadRequest),
+			})
+			return
+		}
+	}
+
+	report := &searchReport{Days: days}
+	var err error
+	report.Top, err = app.analytics.TopQueries(days, reportLimit)
+	if err == nil {
+		report.ZeroResults, err = app.analytics.ZeroResultQueries(days, reportLimit)
+	}
+	if err == nil {
+		report.Trending, err = app.analytics.TrendingQueries(reportLimit)
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.render(w, r, "searchreport.page.tmpl", &templateData{
+		User:         &models.User{UserID: userID, Seller: isSeller},
+		SearchReport: report,
+	})
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"ProjectGoLive/pkg/forms"
+	"ProjectGoLive/pkg/models"
+)
+
+// reviewPageSize is the number of reviews shown on
+// each page of reviews of a product.
+const reviewPageSize = 10
+
+// maxReviewLength is the longest review or reply, in
+// characters.
+const maxReviewLength = 1000
+
+// ratings are the star ratings a review can give.
+var ratings = []string{"1", "2", "3", "4", "5"}
+
+// ReviewForm writes a html form for reviewing the
+// product of an order to the http response.
+func (app *application) ReviewForm(w http.ResponseWriter, r *http.Request) {
+	order, ok := app.reviewableOrder(w, r)
+	if !ok {
+		return
+	}
+
+	app.render(w, r, "review.page.tmpl", &templateData{
+		Form:    forms.New(nil),
+		User:    &models.User{UserID: order.UserID},
+		Order:   order,
+		Ratings: ratings,
+	})
+}
+
+// ReviewCreate inserts a buyer's review of the product
+// of an order into the database, which recomputes the
+// rating of the product, then redirects the client to
+// the product page.
+func (app *application) ReviewCreate(w http.ResponseWriter, r *http.Request) {
+	order, ok := app.reviewableOrder(w, r)
+	if !ok {
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		app.errorLog.Println(ErrInvalidForm)
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
+	form := forms.New(r.PostForm)
+	form.Required("rating", "text")
+	form.PermittedValues("rating", ratings...)
+	form.MaxLength("text", maxReviewLength)
+	if !form.Valid() {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "review.page.tmpl", &templateData{
+			Form:    form,
+			User:    &models.User{UserID: order.UserID},
+			Order:   order,
+			Ratings: ratings,
+		})
+		return
+	}
+
+	rating, _ := strconv.Atoi(form.Get("rating"))
+	_, err = app.reviews.Create(order.OrderID, order.UserID, rating, form.Get("text"))
+	if errors.Is(err, models.ErrDuplicateEntry) {
+		app.session.Put(r, "flash", "You have already reviewed this order.")
+		http.Redirect(w, r, fmt.Sprintf("/product?productid=%v", order.Product.ProductID), http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.session.Put(r, "flash", "Thank you for your review!")
+
+	http.Redirect(w, r, fmt.Sprintf("/product?productid=%v", order.Product.ProductID), http.StatusSeeOther)
+}
+
+// ReviewReply sets a seller's public reply to a review
+// of one of their products, then redirects the client
+// back to the product page.
+func (app *application) ReviewReply(w http.ResponseWriter, r *http.Request) {
+	// only a verified seller can reply to a review
+	isSeller := app.isSeller(r)
+	verified := app.isVerified(r)
+	if !isSeller || !verified {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return
+	}
+	sellerID := app.session.GetString(r, "userid")
+
+	// reviewid & productid parameters should be valid
+	reviewID, err1 := strconv.Atoi(r.URL.Query().Get("reviewid"))
+	productID, err2 := strconv.Atoi(r.URL.Query().Get("productid"))
+	if err1 != nil || err2 != nil {
+		app.errorLog.Println(ErrInvalidQueryParams)
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+	productPage := fmt.Sprintf("/product?productid=%v", productID)
+
+	err := r.ParseForm()
+	if err != nil {
+		app.errorLog.Println(ErrInvalidForm)
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
+	// the reply is written on the product page, so an
+	// invalid reply is reported there
+	form := forms.New(r.PostForm)
+	form.Required("reply")
+	form.MaxLength("reply", maxReviewLength)
+	if !form.Valid() {
+		app.session.Put(r, "flash", "Reply not saved: "+form.Errors.Get("reply"))
+		http.Redirect(w, r, productPage, http.StatusSeeOther)
+		return
+	}
+
+	err = app.reviews.Reply(reviewID, sellerID, form.Get("reply"))
+	if errors.Is(err, models.ErrNoRowsAffected) {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.session.Put(r, "flash", "Reply successfully posted!")
+
+	http.Redirect(w, r, productPage, http.StatusSeeOther)
+}
+
+// reviewableOrder retrieves the order in the orderid
+// parameter if the client can review it: the client
+// placed the order, it has been accepted and it has
+// not been reviewed. Otherwise it writes an error page
+// to the http response and returns false.
+func (app *application) reviewableOrder(w http.ResponseWriter, r *http.Request) (*models.Orders, bool) {
+	// a seller does not place orders
+	if app.isSeller(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return nil, false
+	}
+	userID := app.session.GetString(r, "userid")
+
+	// orderid parameter value should be valid
+	orderID, err := strconv.Atoi(r.URL.Query().Get("orderid"))
+	if err != nil {
+		app.errorLog.Println(ErrInvalidQueryParams)
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return nil, false
+	}
+
+	order, err := app.orders.Get(orderID)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			w.WriteHeader(http.StatusNotFound)
+			app.render(w, r, "error.page.tmpl", &templateData{
+				Error: http.StatusText(http.StatusNotFound),
+			})
+			return nil, false
+		}
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return nil, false
+	}
+
+	// only the buyer of an accepted order can review it
+	if order.UserID != userID || order.Status != 1 {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return nil, false
+	}
+
+	// an order can only be reviewed once
+	if order.Reviewed {
+		app.session.Put(r, "flash", "You have already reviewed this order.")
+		http.Redirect(w, r, fmt.Sprintf("/product?productid=%v", order.Product.ProductID), http.StatusSeeOther)
+		return nil, false
+	}
+
+	return order, true
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"ProjectGoLive/pkg/models"
+	"ProjectGoLive/pkg/promo"
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // ShoppingCart retrieves the shopping cart tagged to
@@ -34,6 +36,13 @@ func (app *application) ShoppingCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the items are still shown at their usual price if
+	// their promotions cannot be retrieved
+	err = app.cartPromotions(cart)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+	}
+
 	// display the list of items
 	app.render(w, r, "shoppingcart.page.tmpl", &templateData{
 		User:         &models.User{UserID: userID},
@@ -59,10 +68,36 @@ func (app *application) AddToCart(w http.ResponseWriter, r *http.Request) {
 	// retrieve userid from session cookie
 	userid := app.session.GetString(r, "userid")
 
-	// retrieve ProductID from url
-	// the ProducID should be valid
-	productID, err := strconv.Atoi(r.URL.Query().Get("productid"))
+	// retrieve ProductID & VariantID from url
+	// both should be valid
+	productID, err1 := strconv.Atoi(r.URL.Query().Get("productid"))
+	variantID, err2 := variantParam(r)
+	if err1 != nil || err2 != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
+	// a product with variants is added as one of its
+	// variants, and a product without as itself
+	variants, err := app.variants.GetProductVariants(productID)
 	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+	valid := len(variants) == 0 && variantID == 0
+	for _, v := range variants {
+		if v.VariantID == variantID {
+			valid = true
+		}
+	}
+	if !valid {
 		w.WriteHeader(http.StatusBadRequest)
 		app.render(w, r, "error.page.tmpl", &templateData{
 			Error: http.StatusText(http.StatusBadRequest),
@@ -71,7 +106,7 @@ func (app *application) AddToCart(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// perform the insert at the database
-	err = app.cart.InsertItem(userid, productID)
+	err = app.cart.InsertItem(userid, productID, variantID)
 	if err != nil {
 		app.errorLog.Println(err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -102,10 +137,11 @@ func (app *application) DeleteFromCart(w http.ResponseWriter, r *http.Request) {
 	// retrieve userid from session cookie
 	userid := app.session.GetString(r, "userid")
 
-	// retrieve ProductID from url
-	// the ProducID should be valid
-	productid, err := strconv.Atoi(r.URL.Query().Get("productid"))
-	if err != nil {
+	// retrieve ProductID & VariantID from url
+	// both should be valid
+	productid, err1 := strconv.Atoi(r.URL.Query().Get("productid"))
+	variantID, err2 := variantParam(r)
+	if err1 != nil || err2 != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		app.render(w, r, "error.page.tmpl", &templateData{
 			Error: http.StatusText(http.StatusBadRequest),
@@ -114,7 +150,7 @@ func (app *application) DeleteFromCart(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// perform the delete at the database
-	err = app.cart.DeleteItem(userid, productid)
+	err := app.cart.DeleteItem(userid, productid, variantID)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		app.render(w, r, "error.page.tmpl", &templateData{
@@ -144,11 +180,12 @@ func (app *application) UpdateItemQty(w http.ResponseWriter, r *http.Request) {
 	// retrieves userid from the session cookie
 	userid := app.session.GetString(r, "userid")
 
-	// retrieve productid & quantity from URL
-	// both should be valid
+	// retrieve productid, variantid & quantity from URL
+	// all should be valid
 	productid, err1 := strconv.Atoi(r.URL.Query().Get("productid"))
 	quantity, err2 := strconv.Atoi(r.URL.Query().Get("quantity"))
-	if err1 != nil || err2 != nil {
+	variantID, err3 := variantParam(r)
+	if err1 != nil || err2 != nil || err3 != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		app.render(w, r, "error.page.tmpl", &templateData{
 			Error: http.StatusText(http.StatusBadRequest),
@@ -157,7 +194,7 @@ func (app *application) UpdateItemQty(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// perform the update at the database
-	err := app.cart.Update(quantity, productid, userid)
+	err := app.cart.Update(quantity, productid, variantID, userid)
 	if err != nil {
 		// if an error did not occur when communicating
 		// with the database, but no row was affected
@@ -206,23 +243,61 @@ func (app *application) CheckOut(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// every item is checked out at the price of the
+	// promotion running on it now, if it has one
+	err = app.cartPromotions(shoppingcart)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
 	// if any item fails the check, it is flagged
 	if !pass {
 		app.render(w, r, "shoppingcart.page.tmpl", &templateData{
 			ShoppingCart: shoppingcart,
 		})
 	} else {
-		// else proceed to create an order for every item
-		for _, v := range shoppingcart {
-			v.UserID = userid
-			err := app.orders.Create(v)
+		// create an order for every item. The units bought
+		// at a promotion's price are claimed from its stock
+		// cap, which they cannot exceed
+		now := time.Now()
+		orders := make([]*models.Orders, len(shoppingcart))
+		for i, v := range shoppingcart {
+			o := &models.Orders{UserID: userid, Qty: v.Qty}
+			o.Product.ProductID, err = strconv.Atoi(v.Product.ProductID)
 			if err != nil {
+				app.errorLog.Println(err)
 				w.WriteHeader(http.StatusInternalServerError)
 				app.render(w, r, "error.page.tmpl", &templateData{
 					Error: http.StatusText(http.StatusInternalServerError),
 				})
 				return
 			}
+			o.Product.VariantID = v.Product.VariantID
+			o.Price = promo.Price(v.Product.Price, v.Product.DiscountID, v.Product.Promotion, now)
+			if o.Price < promo.Price(v.Product.Price, v.Product.DiscountID, nil, now) {
+				o.PromotionID = v.Product.Promotion.PromotionID
+				o.Claimed = v.Qty
+			}
+			orders[i] = o
+		}
+		err = app.orders.Create(orders)
+		if errors.Is(err, models.ErrSoldOut) {
+			app.session.Put(r, "flash", "Not enough units are left at the price of a promotion in your cart, please review the Quantity.")
+			http.Redirect(w, r, "/shoppingcart/", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			app.errorLog.Println(ErrMySQL, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			app.render(w, r, "error.page.tmpl", &templateData{
+				Error: http.StatusText(http.StatusInternalServerError),
+			})
+			return
 		}
 
 		// then delete the user's shopping cart
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"ProjectGoLive/pkg/forms"
+	"ProjectGoLive/pkg/models"
+)
+
+// ProductVariants writes the variants of a product,
+// with html forms for editing them and adding a new
+// one, to the http response.
+func (app *application) ProductVariants(w http.ResponseWriter, r *http.Request) {
+	p, ok := app.sellerProduct(w, r)
+	if !ok {
+		return
+	}
+
+	app.renderVariants(w, r, p, forms.New(nil))
+}
+
+// VariantCreate inserts a new variant of a product into
+// the database then redirects the client back to the
+// variants of the product.
+func (app *application) VariantCreate(w http.ResponseWriter, r *http.Request) {
+	p, ok := app.sellerProduct(w, r)
+	if !ok {
+		return
+	}
+
+	form, ok := app.variantForm(w, r, p)
+	if !ok {
+		return
+	}
+
+	price, _ := strconv.ParseFloat(form.Get("price"), 64)
+	inventory, _ := strconv.Atoi(form.Get("inventory"))
+	_, err := app.variants.Create(p.ProductID, form.Get("name"), price, discountID(form.Get("discount")), inventory)
+	if errors.Is(err, models.ErrDuplicateEntry) {
+		form.Errors.Add("name", "This product already has a variant with this name")
+		w.WriteHeader(http.StatusBadRequest)
+		app.renderVariants(w, r, p, form)
+		return
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.session.Put(r, "flash", "Variant successfully added!")
+
+	http.Redirect(w, r, fmt.Sprintf("/product/variants?productid=%v", p.ProductID), http.StatusSeeOther)
+}
+
+// VariantUpdate edits a variant of a product at the
+// database then redirects the client back to the
+// variants of the product.
+func (app *application) VariantUpdate(w http.ResponseWriter, r *http.Request) {
+	p, ok := app.sellerProduct(w, r)
+	if !ok {
+		return
+	}
+
+	form, ok := app.variantForm(w, r, p)
+	if !ok {
+		return
+	}
+
+	// the variantid form value should be a variant
+	// of the product
+	variantID, err := strconv.Atoi(form.Get("variantid"))
+	if err == nil {
+		_, err = app.variants.Get(p.ProductID, variantID)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
+	price, _ := strconv.ParseFloat(form.Get("price"), 64)
+	inventory, _ := strconv.Atoi(form.Get("inventory"))
+	err = app.variants.Update(p.ProductID, variantID, form.Get("name"), price, discountID(form.Get("discount")), inventory)
+	if errors.Is(err, models.ErrDuplicateEntry) {
+		form.Errors.Add("name", "This product already has a variant with this name")
+		w.WriteHeader(http.StatusBadRequest)
+		app.renderVariants(w, r, p, form)
+		return
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.session.Put(r, "flash", "Variant successfully updated!")
+
+	http.Redirect(w, r, fmt.Sprintf("/product/variants?productid=%v", p.ProductID), http.StatusSeeOther)
+}
+
+// VariantDelete deletes a variant of a product from the
+// database then redirects the client back to the
+// variants of the product.
+func (app *application) VariantDelete(w http.ResponseWriter, r *http.Request) {
+	p, ok := app.sellerProduct(w, r)
+	if !ok {
+		return
+	}
+
+	// the variantid form value should be valid
+	err := r.ParseForm()
+	var variantID int
+	if err == nil {
+		variantID, err = strconv.Atoi(r.PostForm.Get("variantid"))
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return
+	}
+
+	err = app.variants.Delete(p.ProductID, variantID)
+	if errors.Is(err, models.ErrNoRowsAffected) {
+		w.WriteHeader(http.StatusNotFound)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusNotFound),
+		})
+		return
+	}
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return
+	}
+
+	app.session.Put(r, "flash", "Variant successfully deleted.")
+
+	http.Redirect(w, r, fmt.Sprintf("/product/variants?productid=%v", p.ProductID), http.StatusSeeOther)
+}
+
+// sellerProduct retrieves the product in the productid
+// parameter, along with its variants, if it belongs to
+// the client. Otherwise it writes an error page to the
+// http response and returns false.
+func (app *application) sellerProduct(w http.ResponseWriter, r *http.Request) (*models.Product, bool) {
+	// only a verified seller can edit the variants of a product
+	isSeller := app.isSeller(r)
+	verified := app.isVerified(r)
+	if !isSeller || !verified {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return nil, false
+	}
+	sellerID := app.session.GetString(r, "userid")
+
+	// productid parameter value should be valid
+	id, err := strconv.Atoi(r.URL.Query().Get("productid"))
+	if err != nil || id < 1 {
+		app.errorLog.Println(ErrInvalidQueryParams)
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return nil, false
+	}
+
+	p, err := app.products.Get(id)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			w.WriteHeader(http.StatusNotFound)
+			app.render(w, r, "error.page.tmpl", &templateData{
+				Error: http.StatusText(http.StatusNotFound),
+			})
+			return nil, false
+		}
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return nil, false
+	}
+
+	// verify if the product belongs to the client
+	if sellerID != p.SellerID {
+		w.WriteHeader(http.StatusUnauthorized)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusUnauthorized),
+		})
+		return nil, false
+	}
+
+	p.Variants, err = app.variants.GetProductVariants(id)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusInternalServerError),
+		})
+		return nil, false
+	}
+
+	return p, true
+}
+
+// variantForm parses and validates a submitted variant
+// form. If the form is invalid, the variants of the
+// product are written to the http response with the
+// errors, and it returns false.
+func (app *application) variantForm(w http.ResponseWriter, r *http.Request, p *models.Product) (*forms.Form, bool) {
+	err := r.ParseForm()
+	if err != nil {
+		app.errorLog.Println(ErrInvalidForm)
+		w.WriteHeader(http.StatusBadRequest)
+		app.render(w, r, "error.page.tmpl", &templateData{
+			Error: http.StatusText(http.StatusBadRequest),
+		})
+		return nil, false
+	}
+
+	form := forms.New(r.PostForm)
+	form.Required("name", "price", "discount", "inventory")
+	form.MaxLength("name", 50)
+	form.MaxLength("price", 15)
+	form.MatchesPattern("price", forms.PriceRX)
+	form.PermittedValues("discount", models.Discount...)
+	form.MaxLength("inventory", 10)
+	if n, err := strconv.Atoi(form.Get("inventory")); (err != nil || n < 0) && form.Get("inventory") != "" {
+		form.Errors.Add("inventory", "This field must be a whole number")
+	}
+	if !form.Valid() {
+		w.WriteHeader(http.StatusBadRequest)
+		app.renderVariants(w, r, p, form)
+		return nil, false
+	}
+
+	return form, true
+}
+
+// renderVariants writes the variants page of the product
+// with the form to the http response.
+func (app *application) renderVariants(w http.ResponseWriter, r *http.Request, p *models.Product, form *forms.Form) {
+	app.render(w, r, "variants.page.tmpl", &templateData{
+		Form:      form,
+		User:      &models.User{UserID: p.SellerID, Seller: true},
+		Product:   p,
+		Discounts: models.Discount,
+	})
+}
+
+// discountID returns the DiscountID of the discount
+// with the label, or 0 for no discount.
+func discountID(label string) int {
+	for i, v := range models.Discount {
+		if v == label {
+			return i
+		}
+	}
+	return 0
+}
@@ -2,11 +2,19 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"runtime/debug"
+	"strconv"
 	"time"
 
+	"ProjectGoLive/pkg/forms"
+	"ProjectGoLive/pkg/images"
+	"ProjectGoLive/pkg/models"
+	"ProjectGoLive/pkg/sort"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -35,6 +43,323 @@ func (app *application) addDefaultData(td *templateData, r *http.Request) *templ
 	return td
 }
 
+// page sizes used when the size parameter is missing
+// and the largest page size a client can ask for.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageData describes a page of a list of products.
+// First and Last are the positions of the first and
+// last product on the page in the whole list. Prev and
+// Next are zero when there is no such page.
+type pageData struct {
+	Number int
+	Size   int
+	Total  int
+	First  int
+	Last   int
+	Prev   int
+	Next   int
+}
+
+// newPage returns the page of a list of total products
+// selected by the page and size parameters of the URL query.
+// Out of range parameters are moved to the nearest valid value.
+func newPage(total int, query url.Values) *pageData {
+	size, err := strconv.Atoi(query.Get("size"))
+	if err != nil || size < 1 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+
+	pages := (total + size - 1) / size
+	number, err := strconv.Atoi(query.Get("page"))
+	if err != nil || number < 1 {
+		number = 1
+	}
+	if number > pages && pages > 0 {
+		number = pages
+	}
+
+	first := (number - 1) * size
+	last := first + size
+	if last > total {
+		last = total
+	}
+
+	p := &pageData{Number: number, Size: size, Total: total, First: first + 1, Last: last}
+	if number > 1 {
+		p.Prev = number - 1
+	}
+	if number < pages {
+		p.Next = number + 1
+	}
+	return p
+}
+
+// sortSpec returns the sort.Spec selected by the URL query:
+// the preset named by sortby, or else the spec in sort, such
+// as "price:asc,rating:desc". It returns nil if neither is
+// given, and an error if sort is not a valid spec.
+func sortSpec(query url.Values) (sort.Spec, error) {
+	if spec, ok := sort.Presets[query.Get("sortby")]; ok {
+		return spec, nil
+	}
+	if text := query.Get("sort"); text != "" {
+		return sort.ParseSpec(text)
+	}
+	return nil, nil
+}
+
+// variantParam returns the variantid parameter of the
+// URL query, or 0 for a product without variants if
+// there is none.
+func variantParam(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("variantid")
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(v)
+	if err == nil && id < 1 {
+		err = ErrInvalidQueryParams
+	}
+	return id, err
+}
+
+// validateProduct checks the product fields of a form. A
+// product is held to the same rules whether it is created
+// or updated through its form or imported in a catalogue.
+func validateProduct(form *forms.Form) {
+	form.Required("name", "description", "keyword", "price", "category", "discount", "inventory")
+	form.MaxLength("name", 150)
+	form.MaxLength("description", 500)
+	form.MaxLength("price", 15)
+	form.MatchesPattern("price", forms.PriceRX)
+	form.PermittedValues("category", models.Category...)
+	form.PermittedValues("discount", models.Discount...)
+	form.MaxLength("inventory", 10)
+	if n, err := strconv.Atoi(form.Get("inventory")); (err != nil || n < 0) && form.Get("inventory") != "" {
+		form.Errors.Add("inventory", "This field must be a whole number")
+	}
+}
+
+// productFromForm returns the product described by a form
+// which validateProduct has checked.
+func productFromForm(form *forms.Form) *models.Product {
+	price, _ := strconv.ParseFloat(form.Get("price"), 64)
+	inventory, _ := strconv.Atoi(form.Get("inventory"))
+	return &models.Product{
+		Name:       form.Get("name"),
+		Desc:       form.Get("description"),
+		Keyword:    form.Get("keyword"),
+		Price:      price,
+		CategoryID: categoryID(form.Get("category")),
+		DiscountID: discountID(form.Get("discount")),
+		Inventory:  inventory,
+	}
+}
+
+// categoryID returns the CategoryID of the category
+// with the name, or 0 if there is no such category.
+func categoryID(name string) int {
+	for i, v := range models.Category {
+		if v == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// maxProductImages is the most photos a product can have.
+const maxProductImages = 8
+
+// a product form can hold maxProductImages photos and the
+// rest of the form, of which productFormMemory bytes are
+// kept in memory and the rest in temporary files.
+const (
+	maxProductFormSize = maxProductImages*images.MaxUploadSize + 1<<20
+	productFormMemory  = 8 << 20
+)
+
+// errFormTooLarge is returned by parseProductForm for a
+// form larger than maxProductFormSize.
+var errFormTooLarge = errors.New("submitted form is too large")
+
+// parseProductForm parses a product form, which is sent as
+// a multipart form so it can include photos. A form sent
+// without photos is also accepted.
+func parseProductForm(w http.ResponseWriter, r *http.Request) error {
+	if r.ContentLength > maxProductFormSize {
+		return errFormTooLarge
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxProductFormSize)
+
+	err := r.ParseMultipartForm(productFormMemory)
+	if errors.Is(err, http.ErrNotMultipart) {
+		return nil
+	}
+	return err
+}
+
+// savePhotos checks, resizes and saves the photos uploaded
+// in the photos field of the form, of which there can be at
+// most limit. If any photo cannot be saved, an error is added
+// to the form and none of them are kept. savePhotos returns
+// the ImageIDs of the saved photos.
+func (app *application) savePhotos(r *http.Request, form *forms.Form, limit int) []string {
+	if r.MultipartForm == nil {
+		return nil
+	}
+	files := r.MultipartForm.File["photos"]
+	if len(files) > limit {
+		form.Errors.Add("photos", fmt.Sprintf("A product can have at most %d photos", maxProductImages))
+		return nil
+	}
+
+	ids := []string{}
+	for _, fh := range files {
+		f, err := fh.Open()
+		if err != nil {
+			app.errorLog.Println(err)
+			form.Errors.Add("photos", "The photos could not be uploaded")
+			break
+		}
+		img, err := images.Decode(f)
+		f.Close()
+		if errors.Is(err, images.ErrTooLarge) {
+			form.Errors.Add("photos", fmt.Sprintf("%s is larger than %d MB", fh.Filename, images.MaxUploadSize>>20))
+			break
+		}
+		if err != nil {
+			form.Errors.Add("photos", fmt.Sprintf("%s is not a JPEG, PNG or GIF image", fh.Filename))
+			break
+		}
+
+		id, err := images.Save(app.imageStore, img)
+		if err != nil {
+			app.errorLog.Println(err)
+			form.Errors.Add("photos", "The photos could not be uploaded")
+			break
+		}
+		ids = append(ids, id)
+	}
+
+	if !form.Valid() {
+		app.deletePhotos(ids)
+		return nil
+	}
+	return ids
+}
+
+// addPhotos records the saved photos as photos of the
+// product. A photo which cannot be recorded is deleted.
+func (app *application) addPhotos(productID int, ids []string) {
+	for _, id := range ids {
+		err := app.images.Add(productID, id)
+		if err != nil {
+			app.errorLog.Println(ErrMySQL, err)
+			app.deletePhotos([]string{id})
+		}
+	}
+}
+
+// deletePhotos deletes the photos from the image store.
+// A failed delete is only logged as the photo is no longer
+// shown once its row is deleted.
+func (app *application) deletePhotos(ids []string) {
+	for _, id := range ids {
+		err := images.Delete(app.imageStore, id)
+		if err != nil {
+			app.errorLog.Println("Error deleting photo..", err)
+		}
+	}
+}
+
+// productVariants sets the Variants of the products. The
+// products are still shown if the variants cannot be
+// retrieved, so the error is only logged.
+func (app *application) productVariants(products ...*models.Product) {
+	ids := make([]int, len(products))
+	for i, p := range products {
+		ids[i] = p.ProductID
+	}
+
+	variants, err := app.variants.GetVariants(ids)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		return
+	}
+	for _, p := range products {
+		p.Variants = variants[p.ProductID]
+	}
+}
+
+// productPromotions sets the Promotion of the products to
+// the promotion running on them now, if any. The products
+// are still shown at their usual price if the promotions
+// cannot be retrieved, so the error is only logged.
+func (app *application) productPromotions(products ...*models.Product) {
+	ids := make([]int, len(products))
+	for i, p := range products {
+		ids[i] = p.ProductID
+	}
+
+	promotions, err := app.promotions.GetActive(ids, time.Now())
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		return
+	}
+	for _, p := range products {
+		p.Promotion = promotions[p.ProductID]
+	}
+}
+
+// cartPromotions sets the Promotion of the products of the
+// cart items to the promotion running on them now, if any.
+func (app *application) cartPromotions(items []*models.CartItem) error {
+	ids := make([]int, 0, len(items))
+	for _, item := range items {
+		id, err := strconv.Atoi(item.Product.ProductID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	promotions, err := app.promotions.GetActive(ids, time.Now())
+	if err != nil {
+		return err
+	}
+	for i, item := range items {
+		item.Product.Promotion = promotions[ids[i]]
+	}
+	return nil
+}
+
+// productImages sets the Images of the products to the
+// ImageIDs of their photos. The products are still shown
+// if the photos cannot be retrieved, so the error is only
+// logged.
+func (app *application) productImages(products ...*models.Product) {
+	ids := make([]int, len(products))
+	for i, p := range products {
+		ids[i] = p.ProductID
+	}
+
+	photos, err := app.images.GetImages(ids)
+	if err != nil {
+		app.errorLog.Println(ErrMySQL, err)
+		return
+	}
+	for _, p := range products {
+		p.Images = photos[p.ProductID]
+	}
+}
+
 // render executes the template with the specified name &
 // provided template data and writes it to the http response.
 func (app *application) render(w http.ResponseWriter, r *http.Request, name string, td *templateData) {
@@ -94,3 +419,15 @@ func (app *application) isVerified(r *http.Request) bool {
 	}
 	return authUser.Verified
 }
+
+// isAdmin checks the request's context for a
+// value mapped to contextKeyAuthUser to determine
+// if the client is one of the admins listed in
+// the .env file.
+func (app *application) isAdmin(r *http.Request) bool {
+	authUser, ok := r.Context().Value(contextKeyAuthUser).(AuthUser)
+	if !ok {
+		return false
+	}
+	return admins[authUser.UserID]
+}
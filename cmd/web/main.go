@@ -1,6 +1,7 @@
 package main
 
 import (
+	"ProjectGoLive/pkg/images"
 	"ProjectGoLive/pkg/models/mysql"
 	"ProjectGoLive/pkg/search"
 	"crypto/tls"
@@ -10,6 +11,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -42,11 +45,19 @@ type application struct {
 	session  *sessions.Session
 	indSlice *search.IndexSlice //reverse index to hold word index of
 
+	// photo storage
+	imageStore images.Store
+
 	// database connection
-	users    *mysql.UserModel
-	products *mysql.ProductModel
-	cart     *mysql.CartModel
-	orders   *mysql.OrderModel
+	users      *mysql.UserModel
+	products   *mysql.ProductModel
+	images     *mysql.ImageModel
+	variants   *mysql.VariantModel
+	reviews    *mysql.ReviewModel
+	promotions *mysql.PromotionModel
+	cart       *mysql.CartModel
+	orders     *mysql.OrderModel
+	analytics  *mysql.AnalyticsModel
 }
 
 var (
@@ -56,6 +67,23 @@ var (
 	secretKey  string
 	port       string
 	host       string
+
+	// searchSnapshot is the file the search bar
+	// inverted index is saved to between runs
+	searchSnapshot string
+
+	// searchSynonyms is the synonym dictionary
+	// searches are expanded with
+	searchSynonyms string
+
+	// imageDir is the directory the product photos are
+	// kept in, which they are served from at /images/
+	imageDir string
+
+	// admins holds the UserIDs of the users who
+	// can see the search report without being sellers
+	admins map[string]bool
+
 	infoLog    *log.Logger
 	alertLog   *log.Logger
 	errorLog   *log.Logger
@@ -72,6 +100,24 @@ func init() {
 	dbName = goDotEnvVariable("dbName")
 	port = goDotEnvVariable("port")
 	host = goDotEnvVariable("host")
+	searchSnapshot = goDotEnvVariable("searchSnapshot")
+	if searchSnapshot == "" {
+		searchSnapshot = "./data/searchindex.gob"
+	}
+	searchSynonyms = goDotEnvVariable("searchSynonyms")
+	if searchSynonyms == "" {
+		searchSynonyms = "./set-up/synonyms.txt"
+	}
+	imageDir = goDotEnvVariable("imageDir")
+	if imageDir == "" {
+		imageDir = "./data/images"
+	}
+	admins = map[string]bool{}
+	for _, userID := range strings.Split(goDotEnvVariable("admins"), ",") {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			admins[userID] = true
+		}
+	}
 
 }
 
@@ -104,7 +150,12 @@ func main() {
 	}
 	defer db.Close()
 
-	templateCache, err := newTemplateCache("./ui/html/")
+	imageStore, err := images.NewDiskStore(imageDir, "/images/")
+	if err != nil {
+		errorLog.Fatal(err)
+	}
+
+	templateCache, err := newTemplateCache("./ui/html/", imageStore)
 	if err != nil {
 		errorLog.Fatal(err)
 	}
@@ -120,31 +171,58 @@ func main() {
 		fatalLog:      fatalLog,
 		templateCache: templateCache,
 		session:       session,
+		imageStore:    imageStore,
 		users:         &mysql.UserModel{DB: db},
 		products:      &mysql.ProductModel{DB: db},
+		images:        &mysql.ImageModel{DB: db},
+		variants:      &mysql.VariantModel{DB: db},
+		reviews:       &mysql.ReviewModel{DB: db},
+		promotions:    &mysql.PromotionModel{DB: db},
 		cart:          &mysql.CartModel{DB: db},
 		orders:        &mysql.OrderModel{DB: db},
+		analytics:     &mysql.AnalyticsModel{DB: db},
 	}
 
-	app.indSlice = app.makeSearchIndexMap()
+	// build the inverted index for the search bar, starting from
+	// the snapshot saved by the last run so the search bar works
+	// even if the database cannot be reached to catch up
+	app.indSlice = search.NewIndexSlice()
+	app.indSlice.SetBM25(searchBM25())
+	app.loadSynonyms()
+	err = app.indSlice.Load(searchSnapshot)
+	if err == nil {
+		err = app.indSlice.CatchUp(app.products.GetModifiedSearchProducts, app.products.GetProductIDs)
+	} else {
+		alertLog.Println("Search snapshot not loaded, rebuilding inverted map:", err)
+		err = app.indSlice.Rebuild(app.products.GetSearchProducts)
+	}
+	if err != nil {
+		errorLog.Println(err)
+	} else {
+		app.saveSearchIndex()
+	}
 
 	tlsConfig := &tls.Config{
 		PreferServerCipherSuites: true,
 		CurvePreferences:         []tls.CurveID{tls.X25519, tls.CurveP256},
 	}
 
+	// product forms can include photos, which take longer
+	// to upload and resize than the other requests
 	srv := &http.Server{
-		Addr:         host + ":" + port,
-		ErrorLog:     errorLog,
-		Handler:      app.routes(),
-		TLSConfig:    tlsConfig,
-		IdleTimeout:  time.Minute,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		Addr:              host + ":" + port,
+		ErrorLog:          errorLog,
+		Handler:           app.routes(),
+		TLSConfig:         tlsConfig,
+		IdleTimeout:       time.Minute,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      time.Minute,
 	}
 
 	go app.backgroundCleaner()
 	go app.backgroundHelper()
+	go app.synonymWatcher()
 
 	// update to HTTPS eventually
 	infoLog.Printf("Starting server on %s", port)
@@ -183,29 +261,94 @@ func (app *application) backgroundCleaner() {
 
 }
 
-//backgroundHelper is a go routine to perform constant background update for search bar inverted index.
-func (app *application) backgroundHelper() *search.IndexSlice {
+//backgroundHelper is a go routine to perform a periodic consistency
+//check on the search bar inverted index. The index is kept up to date
+//by the product handlers, so a full rebuild is only needed to recover
+//from changes made to the database outside of the application.
+//The rebuilt index is saved so the next start up does not need one.
+func (app *application) backgroundHelper() {
 
-	for range time.Tick(time.Second * 30) {
+	for range time.Tick(time.Hour) {
 
-		app.indSlice = app.makeSearchIndexMap()
+		err := app.indSlice.Rebuild(app.products.GetSearchProducts)
+		if err != nil {
+			app.errorLog.Println(err)
+			continue
+		}
 		infoLog.Printf("Inverted map is refreshed")
+		app.saveSearchIndex()
+
+	}
+}
+
+//synonymWatcher is a go routine which reloads the synonym
+//dictionary whenever the file is edited, so synonyms can be
+//changed without restarting the application.
+func (app *application) synonymWatcher() {
 
+	var modified time.Time
+	if fi, err := os.Stat(searchSynonyms); err == nil {
+		modified = fi.ModTime()
 	}
 
-	return nil
+	for range time.Tick(time.Minute) {
+
+		fi, err := os.Stat(searchSynonyms)
+		if err != nil || fi.ModTime().Equal(modified) {
+			continue
+		}
+		modified = fi.ModTime()
+		app.loadSynonyms()
+
+	}
 }
 
-//function to create inverted index map for search words
-func (app *application) makeSearchIndexMap() *search.IndexSlice {
-	indSlice := search.IndexSlice{map[string][]int{}, map[string][]int{}, map[string][]int{}}
-	productForSearch, err := app.products.GetSearchProducts()
+// loadSynonyms loads the synonym dictionary into the search
+// bar inverted index. If the dictionary cannot be loaded,
+// the synonyms already in use are kept.
+func (app *application) loadSynonyms() {
+	synonyms, err := search.LoadSynonyms(searchSynonyms)
 	if err != nil {
-		app.errorLog.Println(err)
-		return nil
+		app.alertLog.Println("Search synonyms not loaded..", err)
+		return
+	}
+	app.indSlice.SetSynonyms(synonyms)
+	app.infoLog.Printf("Search synonyms loaded from %s", searchSynonyms)
+}
+
+// saveSearchIndex saves a snapshot of the search bar inverted
+// index. A failed save is only logged as the index in memory
+// is unaffected.
+func (app *application) saveSearchIndex() {
+	err := app.indSlice.Save(searchSnapshot)
+	if err != nil {
+		app.errorLog.Println("Error saving search snapshot..", err)
+	}
+}
+
+// searchBM25 returns the parameters used to rank search
+// results. The field boosts can be overridden with the
+// searchBoostName, searchBoostDesc and searchBoostKeyword
+// variables in the .env file.
+func searchBM25() search.BM25 {
+	bm25 := search.DefaultBM25
+	boosts := map[string]*float64{
+		"searchBoostName":    &bm25.Boosts.Name,
+		"searchBoostDesc":    &bm25.Boosts.Desc,
+		"searchBoostKeyword": &bm25.Boosts.Keyword,
+	}
+	for key, boost := range boosts {
+		value := goDotEnvVariable(key)
+		if value == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 {
+			errorLog.Fatalf("Invalid %s in .env file: %q", key, value)
+		}
+		*boost = f
 	}
-	indSlice.Add(productForSearch)
-	return &indSlice
+	return bm25
 }
 
 // use godot package to load/read the .env file and return the value of the key
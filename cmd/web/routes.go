@@ -29,7 +29,25 @@ func (app *application) routes() http.Handler {
 	r.Handle("/product/update", authpipe.ThenFunc(app.ProductUpdateForm)).Methods("GET").Queries("productid", "{productid}")
 	r.Handle("/product/update", authpipe.ThenFunc(app.ProductUpdate)).Methods("POST").Queries("productid", "{productid}")
 	r.Handle("/product/delete", authpipe.ThenFunc(app.ProductDelete)).Methods("GET").Queries("productid", "{productid}")
+	r.Handle("/product/variants", authpipe.ThenFunc(app.ProductVariants)).Methods("GET").Queries("productid", "{productid}")
+	r.Handle("/product/variants", authpipe.ThenFunc(app.VariantCreate)).Methods("POST").Queries("productid", "{productid}")
+	r.Handle("/product/variants/update", authpipe.ThenFunc(app.VariantUpdate)).Methods("POST").Queries("productid", "{productid}")
+	r.Handle("/product/variants/delete", authpipe.ThenFunc(app.VariantDelete)).Methods("POST").Queries("productid", "{productid}")
+	r.Handle("/product/promotions", authpipe.ThenFunc(app.ProductPromotions)).Methods("GET").Queries("productid", "{productid}")
+	r.Handle("/product/promotions", authpipe.ThenFunc(app.PromotionCreate)).Methods("POST").Queries("productid", "{productid}")
+	r.Handle("/product/promotions/delete", authpipe.ThenFunc(app.PromotionDelete)).Methods("POST").Queries("productid", "{productid}")
+	r.Handle("/product/import", authpipe.ThenFunc(app.ProductImport)).Methods("POST")
+	r.Handle("/product/export", authpipe.ThenFunc(app.ProductExport)).Methods("GET")
 	r.Handle("/product/search", stdstack.ThenFunc(app.ProductSearchResults)).Methods("GET").Queries("text", "{text}")
+	r.Handle("/product/suggest", stdstack.ThenFunc(app.ProductSuggest)).Methods("GET").Queries("text", "{text}")
+
+	// REVIEWS
+	r.Handle("/review", authpipe.ThenFunc(app.ReviewForm)).Methods("GET").Queries("orderid", "{orderid}")
+	r.Handle("/review", authpipe.ThenFunc(app.ReviewCreate)).Methods("POST").Queries("orderid", "{orderid}")
+	r.Handle("/review/reply", authpipe.ThenFunc(app.ReviewReply)).Methods("POST").Queries("reviewid", "{reviewid}", "productid", "{productid}")
+
+	// REPORTS
+	r.Handle("/reports/search", authpipe.ThenFunc(app.SearchReport)).Methods("GET")
 
 	// LOG-IN, LOG-OUT
 	r.Handle("/login", stdstack.ThenFunc(app.LogInForm)).Methods("GET")
@@ -59,6 +77,12 @@ func (app *application) routes() http.Handler {
 	// FAVICON
 	r.Handle("/favicon.ico", http.NotFoundHandler())
 
+	// PHOTOS uploaded by sellers, if the store serves
+	// them itself rather than from elsewhere
+	if h, ok := app.imageStore.(http.Handler); ok {
+		r.PathPrefix("/images/").Handler(h)
+	}
+
 	// FILESERVER for style sheets etc.
 	r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("./ui/static/"))))
 
@@ -3,11 +3,16 @@ package main
 import (
 	"html/template"
 	"math"
+	"net/url"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"ProjectGoLive/pkg/forms"
+	"ProjectGoLive/pkg/images"
 	"ProjectGoLive/pkg/models"
+	"ProjectGoLive/pkg/promo"
+	"ProjectGoLive/pkg/search"
 )
 
 // templateData holds all the data to be templated.
@@ -25,18 +30,38 @@ type templateData struct {
 	Categories []string
 	Discounts  []string
 	SortBy     []string
+	Ratings    []string
+
+	Facets   *search.Facets
+	Query    url.Values
+	Page     *pageData
+	Snippets map[int]search.Snippet
+
+	Correction *search.Correction
+
+	SearchReport *searchReport
 
 	ShoppingCart []*models.CartItem
 
 	Orders []*models.Orders
+	Order  *models.Orders
 	Status []string
+
+	Reviews []*models.Review
+
+	Promotions []*models.Promotion
+	Kinds      []string
+
+	Imports []*importRow
+	Columns []string
 }
 
 // newTemplateCache parses all the template files in the
 // specified directory and stores it in a cache for
 // execution later. newTemplateCache should be called when
-// the application is started.
-func newTemplateCache(dir string) (map[string]*template.Template, error) {
+// the application is started. The photos of products are
+// linked to where the store serves them from.
+func newTemplateCache(dir string, store images.Store) (map[string]*template.Template, error) {
 	// Initialize a new map to act as the cache.
 	cache := map[string]*template.Template{}
 
@@ -57,7 +82,7 @@ func newTemplateCache(dir string) (map[string]*template.Template, error) {
 		// call the ParseFiles() method. This means we have to use template.New() to
 		// create an empty template set, use the Funcs() method to register the
 		// template.FuncMap, and then parse the file as normal.
-		ts, err := template.New(name).Funcs(functions).ParseFiles(page)
+		ts, err := template.New(name).Funcs(functions).Funcs(imageFunctions(store)).ParseFiles(page)
 		if err != nil {
 			return nil, err
 		}
@@ -96,6 +121,17 @@ var functions = template.FuncMap{
 	"getStatus":     getStatus,
 	"getFinalPrice": getFinalPrice,
 	"getCartTotal":  getCartTotal,
+
+	"withQuery": withQuery,
+	"stars":     stars,
+
+	"salePrice":   salePrice,
+	"onSale":      onSale,
+	"flashSale":   promo.Flash,
+	"countdown":   countdown,
+	"promoLabel":  promo.Label,
+	"promoStatus": promoStatus,
+	"unitsLeft":   promo.Left,
 }
 
 // humanDate is a template function that returns
@@ -105,6 +141,80 @@ func humanDate(t time.Time) string {
 	return t.Format("02 Jan 2006 at 15:04")
 }
 
+// withQuery is a template function that returns the
+// query string of the current page with key set to
+// value. If value is empty or key is already set to
+// value, key is removed instead so the link toggles the
+// value off. Changing any key other than page goes back
+// to the first page.
+func withQuery(q url.Values, key, value string) string {
+	v := url.Values{}
+	for k, vs := range q {
+		v[k] = vs
+	}
+	if key != "page" {
+		v.Del("page")
+	}
+	if value == "" || q.Get(key) == value {
+		v.Del(key)
+	} else {
+		v.Set(key, value)
+	}
+	return "?" + v.Encode()
+}
+
+// stars is a template function that returns a star
+// rating out of 5 as filled and empty stars.
+func stars(rating int) string {
+	if rating < 0 {
+		rating = 0
+	}
+	if rating > 5 {
+		rating = 5
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+// salePrice is a template function that returns the
+// unit price of a product, or one of its variants, with
+// the price and DiscountID under the promotion now.
+func salePrice(price float64, discountID int, p *models.Promotion) float64 {
+	return promo.Price(price, discountID, p, time.Now())
+}
+
+// onSale is a template function that reports whether
+// the promotion is running now.
+func onSale(p *models.Promotion) bool {
+	return promo.Active(p, time.Now())
+}
+
+// countdown is a template function that returns the
+// time left until the promotion ends.
+func countdown(p *models.Promotion) string {
+	return promo.Countdown(p, time.Now())
+}
+
+// promoStatus is a template function that returns
+// whether the promotion is upcoming, running or over.
+func promoStatus(p *models.Promotion) string {
+	return promo.Status(p, time.Now())
+}
+
+// imageFunctions returns the template functions linking
+// to the photos in the store: thumbURL returns the URL of
+// the thumbnail of the photo with the ImageID, and photoURL
+// the URL of the full size photo.
+func imageFunctions(store images.Store) template.FuncMap {
+	return template.FuncMap{
+		"thumbURL": func(id string) string {
+			return store.URL(images.Name(id, images.Thumbnail))
+		},
+		"photoURL": func(id string) string {
+			return store.URL(images.Name(id, images.Full))
+		},
+	}
+}
+
 // getDiscount is a template function that returns
 // the string representation of the dsicount id.
 func getDiscount(DiscID int) string {
@@ -139,9 +249,10 @@ func getStatus(Status int) string {
 }
 
 // getFinalPrice is a template function that returns
-// the price of a cart item after applying discount.
+// the price of a cart item after applying discount, or
+// its promotion if that gives a lower price.
 func getFinalPrice(item models.CartItem) float64 {
-	finalPrice := float64(item.Qty) * item.Product.Price * models.DiscMultiplier[item.Product.DiscountID]
+	finalPrice := float64(item.Qty) * promo.Exact(item.Product.Price, item.Product.DiscountID, item.Product.Promotion, time.Now())
 	return math.Ceil(finalPrice*100) / 100
 }
 
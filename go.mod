@@ -1,9 +1,8 @@
 module ProjectGoLive
 
-go 1.15
+go 1.18
 
 require (
-	github.com/bbalet/stopwords v1.0.0
 	github.com/go-sql-driver/mysql v1.6.0
 	github.com/golangcollege/sessions v1.2.0
 	github.com/gorilla/mux v1.8.0
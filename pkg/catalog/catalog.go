@@ -0,0 +1,148 @@
+// Package catalog reads and writes a seller's products
+// as CSV, so that a whole catalogue can be exported,
+// edited in a spreadsheet and imported again.
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// Columns are the columns of a catalogue, in the order
+// they are written. A row without a productid is a new
+// product, otherwise it updates that product.
+var Columns = []string{"productid", "name", "description", "keyword", "price", "category", "discount", "inventory"}
+
+var (
+	ErrEmpty         = errors.New("catalog: the file has no header row")
+	ErrMissingColumn = errors.New("catalog: missing column")
+	ErrTooManyRows   = errors.New("catalog: too many rows")
+)
+
+// Row is a row of a catalogue, with its values keyed by
+// column name like a submitted product form. Line is the
+// line of the file the row starts on.
+type Row struct {
+	Line   int
+	Values url.Values
+}
+
+// Read reads the rows of the CSV catalogue in r, of which
+// there can be at most maxRows. The header row names the
+// columns, in any order and case. Every column except
+// productid must be present, and other columns are
+// ignored. Rows which are entirely blank are skipped.
+func Read(r io.Reader, maxRows int) ([]*Row, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, ErrEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// map each known column to its position; a spreadsheet
+	// saved as UTF-8 CSV may start with a byte order mark
+	index := map[string]int{}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := index[name]; !ok {
+			index[name] = i
+		}
+	}
+	for _, c := range Columns[1:] {
+		if _, ok := index[c]; !ok {
+			return nil, fmt.Errorf("%w %q", ErrMissingColumn, c)
+		}
+	}
+
+	rows := []*Row{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blank(record) {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w (maximum is %d)", ErrTooManyRows, maxRows)
+		}
+
+		line, _ := cr.FieldPos(0)
+		row := &Row{Line: line, Values: url.Values{}}
+		for _, c := range Columns {
+			if i, ok := index[c]; ok {
+				row.Values.Set(c, strings.TrimSpace(record[i]))
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// blank reports whether every field of the record is
+// empty, as in the trailing rows of some spreadsheets.
+func blank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Write writes the products to w as a CSV catalogue which
+// Read accepts, naming their category and discount.
+func Write(w io.Writer, products []*models.Product) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(Columns)
+	if err != nil {
+		return err
+	}
+	for _, p := range products {
+		err = cw.Write([]string{
+			strconv.Itoa(p.ProductID),
+			p.Name,
+			p.Desc,
+			p.Keyword,
+			strconv.FormatFloat(p.Price, 'f', -1, 64),
+			label(models.Category, p.CategoryID),
+			label(models.Discount, p.DiscountID),
+			strconv.Itoa(p.Inventory),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// label returns the name with index id in names, or an
+// empty string if there is no such name.
+func label(names []string, id int) string {
+	if id < 0 || id >= len(names) {
+		return ""
+	}
+	return names[id]
+}
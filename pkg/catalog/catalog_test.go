@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"ProjectGoLive/pkg/models"
+)
+
+func Test_Read(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string // name of each row
+		lines   []int
+		wantErr error
+	}{
+		{
+			name:  "Columns in order",
+			text:  "productid,name,description,keyword,price,category,discount,inventory\n3,Rice,5kg bag,rice,12.50,Staples,No Discount,10\n",
+			want:  []string{"Rice"},
+			lines: []int{2},
+		},
+		{
+			name:  "Any order and case without productid",
+			text:  "Inventory,Discount,Category,Price,Keyword,Description,NAME\n10,No Discount,Staples,12.50,rice,5kg bag,Rice\n4,5% discount,Beverages,1,tea,Teh,Tea\n",
+			want:  []string{"Rice", "Tea"},
+			lines: []int{2, 3},
+		},
+		{
+			name:  "Byte order mark and extra columns",
+			text:  "\ufeffname,notes,description,keyword,price,category,discount,inventory\nRice,ignored,5kg bag,rice,12.50,Staples,No Discount,10\n",
+			want:  []string{"Rice"},
+			lines: []int{2},
+		},
+		{
+			name:  "Blank and quoted rows",
+			text:  "name,description,keyword,price,category,discount,inventory\n,,,,,,\n\"Rice\",\"5kg\nbag\",rice,12.50,Staples,No Discount,10\n,,,,,,\nTea,Teh,tea,1,Beverages,No Discount,4\n",
+			want:  []string{"Rice", "Tea"},
+			lines: []int{3, 6},
+		},
+		{
+			name:    "Missing column",
+			text:    "name,description,keyword,price,category,inventory\nRice,5kg bag,rice,12.50,Staples,10\n",
+			wantErr: ErrMissingColumn,
+		},
+		{
+			name:    "Too many rows",
+			text:    "name,description,keyword,price,category,discount,inventory\na,b,c,1,Staples,No Discount,1\na,b,c,1,Staples,No Discount,1\na,b,c,1,Staples,No Discount,1\n",
+			wantErr: ErrTooManyRows,
+		},
+		{
+			name:    "Empty",
+			text:    "",
+			wantErr: ErrEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Read(strings.NewReader(tt.text), 2)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Read() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("Read() got %d rows, want %d", len(rows), len(tt.want))
+			}
+			for i, row := range rows {
+				if got := row.Values.Get("name"); got != tt.want[i] {
+					t.Errorf("row %d name = %q, want %q", i, got, tt.want[i])
+				}
+				if row.Line != tt.lines[i] {
+					t.Errorf("row %d line = %d, want %d", i, row.Line, tt.lines[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_Read_RaggedRow(t *testing.T) {
+	text := "name,description,keyword,price,category,discount,inventory\nRice,5kg bag,rice\n"
+	if _, err := Read(strings.NewReader(text), 10); err == nil {
+		t.Error("Read() of a row with missing fields did not fail")
+	}
+}
+
+func Test_WriteRead(t *testing.T) {
+	products := []*models.Product{
+		{ProductID: 1, Name: "Rice, Thai", Desc: "5kg \"fragrant\" bag", Keyword: "rice beras", Price: 12.5, CategoryID: 1, DiscountID: 0, Inventory: 10},
+		{ProductID: 22, Name: "Teh", Desc: "line one\nline two", Keyword: "tea", Price: 1, CategoryID: 3, DiscountID: 2, Inventory: 0},
+	}
+
+	var buf bytes.Buffer
+	err := Write(&buf, products)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := Read(&buf, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(products) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(products))
+	}
+
+	want := [][]string{
+		{"1", "Rice, Thai", "5kg \"fragrant\" bag", "rice beras", "12.5", "Staples", "No Discount", "10"},
+		{"22", "Teh", "line one\nline two", "tea", "1", "Beverages", "10% discount", "0"},
+	}
+	for i, row := range rows {
+		for j, c := range Columns {
+			if got := row.Values.Get(c); got != want[i][j] {
+				t.Errorf("row %d %s = %q, want %q", i, c, got, want[i][j])
+			}
+		}
+	}
+}
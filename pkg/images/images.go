@@ -0,0 +1,196 @@
+// Package images checks, resizes and stores the photos
+// sellers upload for their products.
+package images
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+var (
+	ErrTooLarge        = errors.New("images: image is too large")
+	ErrUnsupportedType = errors.New("images: image type is not supported")
+)
+
+// MaxUploadSize is the largest file, in bytes, which is
+// accepted as a photo, and MaxPixels the most pixels its
+// image may have once decoded.
+const (
+	MaxUploadSize = 5 << 20
+	MaxPixels     = 25_000_000
+)
+
+// types are the content types of the photos which can
+// be uploaded, all of which are decoded by the standard
+// library.
+var types = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Size is a size a photo is saved in. The photo is
+// scaled down to fit within Width and Height, keeping
+// its aspect ratio, but is never scaled up.
+type Size struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+var (
+	Thumbnail = Size{Name: "thumb", Width: 240, Height: 240}
+	Full      = Size{Name: "full", Width: 1200, Height: 1200}
+)
+
+// Sizes are the sizes every photo is saved in.
+var Sizes = []Size{Thumbnail, Full}
+
+// quality is the JPEG quality photos are saved with.
+const quality = 85
+
+// Decode reads an uploaded photo. It returns ErrTooLarge if
+// the file is larger than MaxUploadSize or the image has
+// more than MaxPixels, and ErrUnsupportedType if it is not
+// a JPEG, PNG or GIF image, whatever its file name says.
+func Decode(r io.Reader) (image.Image, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxUploadSize {
+		return nil, ErrTooLarge
+	}
+	if !types[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedType
+	}
+
+	// check the dimensions before decoding, since a small
+	// file can hold an image too large to fit in memory
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	return img, nil
+}
+
+// Save resizes the photo to every size in Sizes and saves
+// each as a JPEG in the store. Re-encoding the photo also
+// drops any metadata it had, such as where it was taken.
+// Save returns the ID the saved images are named with.
+func Save(s Store, img image.Image) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(b)
+
+	for _, size := range Sizes {
+		var buf bytes.Buffer
+		err := jpeg.Encode(&buf, Resize(img, size.Width, size.Height), &jpeg.Options{Quality: quality})
+		if err != nil {
+			return "", err
+		}
+		if err := s.Put(Name(id, size), &buf); err != nil {
+			Delete(s, id)
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+// Delete removes every size of the photo with the ID
+// from the store.
+func Delete(s Store, id string) error {
+	var first error
+	for _, size := range Sizes {
+		if err := s.Delete(Name(id, size)); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Name returns the name the photo with the ID is
+// saved under in the size.
+func Name(id string, size Size) string {
+	return fmt.Sprintf("%s_%s.jpg", id, size.Name)
+}
+
+// Resize scales the image down to fit within width and
+// height, keeping its aspect ratio, and draws it onto a
+// white background so transparent parts of PNG and GIF
+// images are white once saved as a JPEG. Each pixel of
+// the result is the average of the pixels it covers.
+func Resize(img image.Image, width, height int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > width || h > height {
+		// scale by whichever side overflows the most
+		if w*height > h*width {
+			w, h = width, max(1, h*width/w)
+		} else {
+			w, h = max(1, w*height/h), height
+		}
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Over)
+	if w == b.Dx() && h == b.Dy() {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*b.Dy()/h, max((y+1)*b.Dy()/h, y*b.Dy()/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := x*b.Dx()/w, max((x+1)*b.Dx()/w, x*b.Dx()/w+1)
+
+			var r, g, bl, n int
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					bl += int(src.Pix[i+2])
+					i += 4
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(bl / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,168 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testImage returns a w by h image, red on the left
+// half and transparent on the right.
+func testImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w/2; x++ {
+			img.Set(x, y, color.NRGBA{R: 0xff, A: 0xff})
+		}
+	}
+	return img
+}
+
+func encode(t *testing.T, format string, img image.Image) []byte {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_Decode(t *testing.T) {
+	img := testImage(40, 20)
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "PNG", data: encode(t, "png", img)},
+		{name: "JPEG", data: encode(t, "jpeg", img)},
+		{name: "GIF", data: encode(t, "gif", img)},
+		{name: "text", data: []byte("not a photo"), err: ErrUnsupportedType},
+		{name: "HTML", data: []byte("<html><body>hi</body></html>"), err: ErrUnsupportedType},
+		{name: "truncated PNG", data: encode(t, "png", img)[:40], err: ErrUnsupportedType},
+		{name: "too large file", data: append(encode(t, "png", img), make([]byte, MaxUploadSize)...), err: ErrTooLarge},
+		{name: "too many pixels", data: encode(t, "png", image.NewGray(image.Rect(0, 0, 8000, 6000))), err: ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err == nil && got.Bounds().Dx() != 40 {
+				t.Errorf("got width %v, want 40", got.Bounds().Dx())
+			}
+		})
+	}
+}
+
+func Test_Resize(t *testing.T) {
+	tests := []struct {
+		name          string
+		w, h          int
+		width, height int
+		wantW, wantH  int
+	}{
+		{name: "smaller", w: 100, h: 50, width: 200, height: 200, wantW: 100, wantH: 50},
+		{name: "wide", w: 400, h: 100, width: 200, height: 200, wantW: 200, wantH: 50},
+		{name: "tall", w: 100, h: 400, width: 200, height: 200, wantW: 50, wantH: 200},
+		{name: "square", w: 300, h: 300, width: 200, height: 100, wantW: 100, wantH: 100},
+		{name: "thin", w: 1000, h: 1, width: 200, height: 200, wantW: 200, wantH: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Resize(testImage(tt.w, tt.h), tt.width, tt.height).Bounds()
+			if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+				t.Errorf("got %vx%v, want %vx%v", got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+
+	// the transparent half is drawn onto white
+	got := Resize(testImage(400, 200), 100, 100)
+	if r, g, b, _ := got.At(10, 10).RGBA(); r>>8 != 0xff || g != 0 || b != 0 {
+		t.Errorf("got left pixel %v, want red", got.At(10, 10))
+	}
+	if r, g, b, _ := got.At(90, 10).RGBA(); r>>8 != 0xff || g>>8 != 0xff || b>>8 != 0xff {
+		t.Errorf("got right pixel %v, want white", got.At(90, 10))
+	}
+}
+
+func Test_DiskStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewDiskStore(filepath.Join(dir, "images"), "/images/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := Save(s, testImage(2000, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range Sizes {
+		name := Name(id, size)
+		if got, want := s.URL(name), "/images/"+name; got != want {
+			t.Errorf("got URL %q, want %q", got, want)
+		}
+
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, s.URL(name), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %v for %v, want 200", rec.Code, name)
+		}
+		img, err := jpeg.Decode(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := img.Bounds().Dx(); got != size.Width {
+			t.Errorf("got %v width %v, want %v", size.Name, got, size.Width)
+		}
+	}
+
+	for _, path := range []string{"/images/", "/images/missing.jpg", "/images/.upload-1", "/images/../images.go"} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("got status %v for %v, want 404", rec.Code, path)
+		}
+	}
+
+	for _, name := range []string{"", "..", "../x.jpg", `a\b.jpg`} {
+		if err := s.Put(name, bytes.NewReader(nil)); err != ErrInvalidName {
+			t.Errorf("Put(%q) got error %v, want %v", name, err, ErrInvalidName)
+		}
+	}
+
+	if err := Delete(s, id); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "images"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %v files left after Delete, want none", len(entries))
+	}
+	if err := Delete(s, id); err != nil {
+		t.Errorf("deleting again got error %v, want nil", err)
+	}
+}
@@ -0,0 +1,119 @@
+package images
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidName is returned by a Store for a name which
+// is not a plain file name.
+var ErrInvalidName = errors.New("images: invalid image name")
+
+// Store holds the saved images. URL returns where the
+// image with the name is served from.
+type Store interface {
+	Put(name string, r io.Reader) error
+	Delete(name string) error
+	URL(name string) string
+}
+
+// DiskStore is a Store which keeps the images in a
+// directory on the local disk. It is also the http.Handler
+// serving them, from the URL prefix.
+type DiskStore struct {
+	dir    string
+	prefix string
+}
+
+// NewDiskStore returns a DiskStore keeping the images in
+// dir, which is created if it does not exist, and serving
+// them from the URL prefix, such as "/images/".
+func NewDiskStore(dir, prefix string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskStore{dir: dir, prefix: prefix}, nil
+}
+
+// validName reports whether the name is a plain file name,
+// so it cannot be used to reach outside the directory.
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// Put writes the image to a temporary file which is then
+// renamed, so an image is never served half written.
+func (s *DiskStore) Put(name string, r io.Reader) error {
+	if !validName(name) {
+		return ErrInvalidName
+	}
+
+	f, err := ioutil.TempFile(s.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(s.dir, name))
+}
+
+// Delete removes the image. Deleting an image which does
+// not exist is not an error.
+func (s *DiskStore) Delete(name string) error {
+	if !validName(name) {
+		return ErrInvalidName
+	}
+	err := os.Remove(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// URL returns the URL prefix followed by the name.
+func (s *DiskStore) URL(name string) string {
+	return s.prefix + name
+}
+
+// ServeHTTP serves the image named by the last element of
+// the URL path. Images are never changed once saved, so
+// they can be cached for as long as the client likes.
+func (s *DiskStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := path.Base(r.URL.Path)
+	if !validName(name) || strings.HasPrefix(name, ".") {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
@@ -8,15 +8,25 @@ import (
 var ErrNoRecord = errors.New("models: no matching record found")
 var ErrNoRowsAffected = errors.New("models: no rows affected")
 var ErrDuplicateEntry = errors.New("models: duplicate entry in username/email detected")
+var ErrOverlapping = errors.New("models: overlapping promotion")
+var ErrSoldOut = errors.New("models: promotion sold out")
 
 var Category = []string{"Frozen Food", "Staples", "Meat and Seafood", "Beverages", "Fruit and Vegetables"}
-var SortBy = []string{"Popular", "Highly Rated", "Price (Asc.)", "Price (Desc.)"}
+var SortBy = []string{"Popular", "Highly Rated", "Price (Asc.)", "Price (Desc.)", "Newest", "Recently Updated", "Biggest Discount", "Best Value", "In Stock"}
 
 var Discount = []string{"No Discount", "5% discount", "10% discount", "15% discount", "20% discount", "25% discount"}
 var DiscMultiplier = []float64{1, .95, .9, .85, .8, .75}
 
 var Status = []string{"Pending", "Accepted", "Cancelled"}
 
+// the kinds of promotion: a percentage or an amount off.
+const (
+	PromotionPercent = iota
+	PromotionAmount
+)
+
+var PromotionKind = []string{"Percent off", "Amount off"}
+
 var MapOTP = make(map[string]string)
 
 type Product struct {
@@ -34,7 +44,49 @@ type Product struct {
 	SellerID   string
 	Created    time.Time
 	Modified   time.Time
-	Score      int
+	Score      float64
+	Images     []string
+	Variants   []*Variant
+	Promotion  *Promotion
+}
+
+// Variant is a size, weight or pack of a product which
+// is sold with its own price, discount and inventory.
+// The Price, DiscountID and Inventory of a product with
+// variants are rolled up from them, so the product is
+// listed once: at the price and discount of the variant
+// which is cheapest once discounted, with the stock of
+// every variant.
+type Variant struct {
+	VariantID  int
+	ProductID  int
+	Name       string
+	Price      float64
+	DiscountID int
+	Inventory  int
+	UnitSold   int
+	Created    time.Time
+	Modified   time.Time
+}
+
+// Promotion is a discount a seller schedules on a product,
+// and all of its variants, from Starts until Ends. It
+// takes Value percent or Value dollars off the price,
+// depending on its Kind, for at most StockCap units, or
+// any number of them if StockCap is 0. Sold is the number
+// of units checked out at the promotion's price, less those
+// of cancelled orders.
+type Promotion struct {
+	PromotionID int
+	ProductID   int
+	Name        string
+	Kind        int
+	Value       float64
+	Starts      time.Time
+	Ends        time.Time
+	StockCap    int
+	Sold        int
+	Created     time.Time
 }
 
 type User struct {
@@ -50,18 +102,56 @@ type User struct {
 	Created     time.Time
 }
 
+// Orders is an order of a product checked out by a
+// buyer at Price per unit. Claimed is the number of its
+// units counted towards the stock cap of the promotion
+// with PromotionID.
 type Orders struct {
 	OrderID int
 	UserID  string
 	Product struct {
 		ProductID int
 		Name      string
+		VariantID int
+		Variant   string
 	}
-	Qty      int
-	SellerID string
-	Status   int
-	Created  time.Time
-	Modified time.Time
+	Qty         int
+	Price       float64
+	PromotionID int
+	Claimed     int
+	SellerID    string
+	Status      int
+	Reviewed    bool
+	Created     time.Time
+	Modified    time.Time
+}
+
+// Review is the star rating and text a buyer left for a
+// product they ordered, with the seller's public reply.
+// Reply is empty until the seller has replied.
+type Review struct {
+	ReviewID  int
+	OrderID   int
+	ProductID int
+	UserID    string
+	Rating    int
+	Text      string
+	Reply     string
+	Replied   time.Time
+	Created   time.Time
+}
+
+// QueryStat summarises the searches made with the same
+// query. Results is the average number of results and
+// Clicks the number of searches where a result was
+// clicked. Previous is the number of searches made in
+// the period before, for finding trending queries.
+type QueryStat struct {
+	Query    string
+	Searches int
+	Clicks   int
+	Results  int
+	Previous int
 }
 
 type CartItem struct {
@@ -73,6 +163,9 @@ type CartItem struct {
 		Inventory  int
 		Price      float64
 		SellerID   string
+		VariantID  int
+		Variant    string
+		Promotion  *Promotion
 	}
 	Qty      int
 	Invalid  bool
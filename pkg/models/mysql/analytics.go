@@ -0,0 +1,128 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// AnalyticsModel wraps a sql.DB connection pool and
+// records the searches made by buyers.
+type AnalyticsModel struct {
+	DB *sql.DB
+}
+
+// RecordSearch inserts a new row for a search with the
+// number of products found, and returns its QueryID.
+// Queries are stored in lower case with single spaces
+// so the same search typed differently is counted once.
+func (m *AnalyticsModel) RecordSearch(query string, results int) (int, error) {
+	stmt := `INSERT INTO SearchQuery (Query, Results) VALUES (?, ?)`
+
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if rs := []rune(query); len(rs) > 255 {
+		query = string(rs[:255])
+	}
+
+	result, err := m.DB.Exec(stmt, query, results)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// RecordClick marks the search which has the specified
+// QueryID as clicked, with the ProductID of the product
+// clicked on.
+func (m *AnalyticsModel) RecordClick(queryID, productID int) error {
+	stmt := `UPDATE SearchQuery SET Clicked = 1, ProductID = ? WHERE QueryID = ?`
+
+	result, err := m.DB.Exec(stmt, productID, queryID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// TopQueries retrieves the queries searched for most often
+// in the last specified number of days.
+func (m *AnalyticsModel) TopQueries(days, limit int) ([]*models.QueryStat, error) {
+	stmt := `SELECT Query, COUNT(*), SUM(Clicked), ROUND(AVG(Results)), 0
+			 FROM SearchQuery
+			 WHERE Created >= NOW() - INTERVAL ? DAY
+			 GROUP BY Query
+			 ORDER BY COUNT(*) DESC, Query
+			 LIMIT ?`
+
+	return m.queryStats(stmt, days, limit)
+}
+
+// ZeroResultQueries retrieves the queries which found no
+// products most often in the last specified number of days.
+func (m *AnalyticsModel) ZeroResultQueries(days, limit int) ([]*models.QueryStat, error) {
+	stmt := `SELECT Query, COUNT(*), SUM(Clicked), 0, 0
+			 FROM SearchQuery
+			 WHERE Created >= NOW() - INTERVAL ? DAY AND Results = 0
+			 GROUP BY Query
+			 ORDER BY COUNT(*) DESC, Query
+			 LIMIT ?`
+
+	return m.queryStats(stmt, days, limit)
+}
+
+// TrendingQueries retrieves the queries searched for more
+// often in the last 7 days than in the 7 days before, by
+// the number of searches gained.
+func (m *AnalyticsModel) TrendingQueries(limit int) ([]*models.QueryStat, error) {
+	stmt := `SELECT Query,
+				SUM(Created >= NOW() - INTERVAL 7 DAY) AS ThisWeek,
+				SUM(Clicked AND Created >= NOW() - INTERVAL 7 DAY),
+				ROUND(AVG(Results)),
+				SUM(Created < NOW() - INTERVAL 7 DAY) AS LastWeek
+			 FROM SearchQuery
+			 WHERE Created >= NOW() - INTERVAL 14 DAY
+			 GROUP BY Query
+			 HAVING ThisWeek > LastWeek
+			 ORDER BY ThisWeek - LastWeek DESC, Query
+			 LIMIT ?`
+
+	return m.queryStats(stmt, limit)
+}
+
+// queryStats runs a query selecting the columns of
+// a QueryStat and scans the rows.
+func (m *AnalyticsModel) queryStats(stmt string, args ...interface{}) ([]*models.QueryStat, error) {
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []*models.QueryStat{}
+	for rows.Next() {
+		s := &models.QueryStat{}
+		err = rows.Scan(&s.Query, &s.Searches, &s.Clicks, &s.Results, &s.Previous)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
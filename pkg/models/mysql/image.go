@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// ImageModel wraps a sql.DB connection pool and records
+// which photos belong to which product. The photos
+// themselves are kept in an images.Store.
+type ImageModel struct {
+	DB *sql.DB
+}
+
+// Add inserts a new row for the photo with the ImageID,
+// after the photos the product already has.
+func (m *ImageModel) Add(productID int, imageID string) error {
+	stmt := `INSERT INTO ProductImage (ImageID, ProductID, Position)
+			SELECT ?, ?, COALESCE(MAX(Position) + 1, 0)
+			FROM ProductImage WHERE ProductID = ?`
+
+	_, err := m.DB.Exec(stmt, imageID, productID, productID)
+	return err
+}
+
+// Delete deletes the row of the photo which has the
+// specified ImageID, if it belongs to the product.
+func (m *ImageModel) Delete(productID int, imageID string) error {
+	stmt := `DELETE FROM ProductImage WHERE ImageID = ? AND ProductID = ?`
+
+	result, err := m.DB.Exec(stmt, imageID, productID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// GetProductImages retrieves the ImageID of every photo
+// of the product, in the order they were added.
+func (m *ImageModel) GetProductImages(productID int) ([]string, error) {
+	images, err := m.GetImages([]int{productID})
+	if err != nil {
+		return nil, err
+	}
+	return images[productID], nil
+}
+
+// GetImages retrieves the ImageID of every photo of the
+// products with the specified ProductID column values,
+// mapped by ProductID, in the order they were added.
+func (m *ImageModel) GetImages(productIDs []int) (map[int][]string, error) {
+	images := map[int][]string{}
+	if len(productIDs) == 0 {
+		return images, nil
+	}
+
+	args := make([]interface{}, len(productIDs))
+	for i, id := range productIDs {
+		args[i] = id
+	}
+	stmt := `SELECT ProductID, ImageID FROM ProductImage
+			WHERE ProductID IN (?` + strings.Repeat(",?", len(productIDs)-1) + `)
+			ORDER BY ProductID, Position`
+
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var imageID string
+		err = rows.Scan(&productID, &imageID)
+		if err != nil {
+			return nil, err
+		}
+		images[productID] = append(images[productID], imageID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
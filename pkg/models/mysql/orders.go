@@ -12,19 +12,50 @@ type OrderModel struct {
 	DB *sql.DB
 }
 
-// Create inserts a new row into the orders table
-// using information from a CartItem.
-func (m *OrderModel) Create(c *models.CartItem) error {
+// Create inserts a new row into the orders table for
+// every order checked out, with the unit price it was
+// checked out at. The units Claimed by an order are
+// counted towards the stock cap of its promotion. Either
+// every order is created or, if any promotion does not
+// have enough units left, none are and models.ErrSoldOut
+// is returned.
+func (m *OrderModel) Create(orders []*models.Orders) error {
 	stmt :=
-		`INSERT INTO orders (UserID, ProductID,	Qty, SellerID, Status)
-		 VALUES (?, ?, ?, (SELECT SellerID FROM Product WHERE ProductID = ?), 0);`
+		`INSERT INTO orders (UserID, ProductID, VariantID, Qty, Price, PromotionID, Claimed, SellerID, Status)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, (SELECT SellerID FROM Product WHERE ProductID = ?), 0);`
+	claim := `UPDATE Promotion SET Sold = Sold + ?
+			WHERE PromotionID = ? AND (StockCap = 0 OR Sold + ? <= StockCap)`
 
-	_, err := m.DB.Exec(stmt, c.UserID, c.Product.ProductID, c.Qty, c.Product.ProductID)
+	tx, err := m.DB.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	for _, o := range orders {
+		var promotionID interface{}
+		if o.Claimed > 0 {
+			result, err := tx.Exec(claim, o.Claimed, o.PromotionID, o.Claimed)
+			if err != nil {
+				return err
+			}
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if rows == 0 {
+				return models.ErrSoldOut
+			}
+			promotionID = o.PromotionID
+		}
 
-	return nil
+		_, err = tx.Exec(stmt, o.UserID, o.Product.ProductID, o.Product.VariantID, o.Qty, o.Price, promotionID, o.Claimed, o.Product.ProductID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // GetAll retrieves all the orders which has the
@@ -35,17 +66,23 @@ func (m *OrderModel) GetAll(userID string, isSeller bool) ([]*models.Orders, err
 	var stmt string
 	if !isSeller {
 		stmt = `SELECT 
-					Orders.OrderID,	Orders.UserID, Product.Name,
-					Orders.Qty,	Orders.SellerID, Orders.Status
+					Orders.OrderID,	Orders.UserID, Orders.ProductID, Product.Name,
+					Orders.VariantID, COALESCE(ProductVariant.Name, ''),
+					Orders.Qty,	COALESCE(Orders.Price, 0), COALESCE(Orders.PromotionID, 0), Orders.Claimed, Orders.SellerID, Orders.Status, Review.ReviewID IS NOT NULL
 				FROM Orders 
 				LEFT JOIN Product ON Orders.ProductID = Product.ProductID
+				LEFT JOIN ProductVariant ON Orders.VariantID = ProductVariant.VariantID
+				LEFT JOIN Review ON Orders.OrderID = Review.OrderID
 				WHERE Orders.UserID=?`
 	} else {
 		stmt = `SELECT
-					Orders.OrderID, Orders.UserID, Product.Name,
-					Orders.Qty, Orders.SellerID, Orders.Status
+					Orders.OrderID, Orders.UserID, Orders.ProductID, Product.Name,
+					Orders.VariantID, COALESCE(ProductVariant.Name, ''),
+					Orders.Qty, COALESCE(Orders.Price, 0), COALESCE(Orders.PromotionID, 0), Orders.Claimed, Orders.SellerID, Orders.Status, Review.ReviewID IS NOT NULL
 				FROM Orders 
 				LEFT JOIN Product ON Orders.ProductID = Product.ProductID
+				LEFT JOIN ProductVariant ON Orders.VariantID = ProductVariant.VariantID
+				LEFT JOIN Review ON Orders.OrderID = Review.OrderID
 				WHERE Orders.SellerID=?`
 	}
 
@@ -62,10 +99,17 @@ func (m *OrderModel) GetAll(userID string, isSeller bool) ([]*models.Orders, err
 		err = rows.Scan(
 			&order.OrderID,
 			&order.UserID,
+			&order.Product.ProductID,
 			&order.Product.Name,
+			&order.Product.VariantID,
+			&order.Product.Variant,
 			&order.Qty,
+			&order.Price,
+			&order.PromotionID,
+			&order.Claimed,
 			&order.SellerID,
 			&order.Status,
+			&order.Reviewed,
 		)
 		if err != nil {
 			return nil, err
@@ -78,13 +122,25 @@ func (m *OrderModel) GetAll(userID string, isSeller bool) ([]*models.Orders, err
 
 // UpdateStatus edits the status column value
 // for the row which has the specified orderID
-// column value.
+// column value. Accepting an order takes its
+// quantity from the inventory of the product,
+// and of its variant if it has one. Cancelling
+// a pending order gives the units it claimed
+// back to its promotion.
 func (m *OrderModel) UpdateStatus(orderid, status int) error {
 	var stmt string
-	if status == 1 {
-		stmt = `UPDATE Product, Orders 
-	SET Orders.Status = ?, Product.Inventory = Product.Inventory - Orders.Qty, Product.UnitSold = Product.UnitSold + Orders.Qty
-	WHERE Orders.ProductID = Product.ProductID AND Orders.OrderID = ?`
+	if status == 2 {
+		stmt = `UPDATE Orders
+	LEFT JOIN Promotion ON Orders.PromotionID = Promotion.PromotionID
+	SET Orders.Status = ?, Promotion.Sold = GREATEST(Promotion.Sold - Orders.Claimed, 0)
+	WHERE Orders.OrderID = ? AND Orders.Status = 0`
+	} else if status == 1 {
+		stmt = `UPDATE Orders
+	JOIN Product ON Orders.ProductID = Product.ProductID
+	LEFT JOIN ProductVariant ON Orders.VariantID = ProductVariant.VariantID
+	SET Orders.Status = ?, Product.Inventory = Product.Inventory - Orders.Qty, Product.UnitSold = Product.UnitSold + Orders.Qty, Product.Modified = NOW(),
+		ProductVariant.Inventory = ProductVariant.Inventory - Orders.Qty, ProductVariant.UnitSold = ProductVariant.UnitSold + Orders.Qty, ProductVariant.Modified = NOW()
+	WHERE Orders.OrderID = ?`
 	} else {
 		stmt = `UPDATE Orders SET Status = ? Where OrderID = ?`
 	}
@@ -101,10 +157,13 @@ func (m *OrderModel) UpdateStatus(orderid, status int) error {
 func (m *OrderModel) Get(orderID int) (*models.Orders, error) {
 
 	stmt := `SELECT
-	Orders.OrderID, Orders.UserID, Product.Name,
-	Orders.Qty, Orders.SellerID, Orders.Status
+	Orders.OrderID, Orders.UserID, Orders.ProductID, Product.Name,
+	Orders.VariantID, COALESCE(ProductVariant.Name, ''),
+	Orders.Qty, COALESCE(Orders.Price, 0), COALESCE(Orders.PromotionID, 0), Orders.Claimed, Orders.SellerID, Orders.Status, Review.ReviewID IS NOT NULL
 	FROM Orders 
 	LEFT JOIN Product ON Orders.ProductID = Product.ProductID
+	LEFT JOIN ProductVariant ON Orders.VariantID = ProductVariant.VariantID
+	LEFT JOIN Review ON Orders.OrderID = Review.OrderID
 	WHERE Orders.OrderID=?`
 
 	row := m.DB.QueryRow(stmt, orderID)
@@ -113,10 +172,17 @@ func (m *OrderModel) Get(orderID int) (*models.Orders, error) {
 	err := row.Scan(
 		&order.OrderID,
 		&order.UserID,
+		&order.Product.ProductID,
 		&order.Product.Name,
+		&order.Product.VariantID,
+		&order.Product.Variant,
 		&order.Qty,
+		&order.Price,
+		&order.PromotionID,
+		&order.Claimed,
 		&order.SellerID,
 		&order.Status,
+		&order.Reviewed,
 	)
 
 	if err != nil {
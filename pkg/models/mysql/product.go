@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"ProjectGoLive/pkg/models"
 )
@@ -40,7 +41,7 @@ func (m *ProductModel) Create(name, desc, keyword, sellerID string, price float6
 // has the specified ProductID column value.
 func (m *ProductModel) Update(name, desc, keyword string, price float64, inventory, ID, catID, discID int) error {
 	stmt := `UPDATE Product 
-			 SET Name=?, Description=?, Keyword=?, Price=?, CategoryID=?, DiscountID=?, inventory=?
+			 SET Name=?, Description=?, Keyword=?, Price=?, CategoryID=?, DiscountID=?, inventory=?, Modified=NOW()
 			 WHERE ProductID = ?`
 
 	_, err := m.DB.Exec(stmt, name, desc, keyword, price, catID, discID, inventory, ID)
@@ -124,12 +125,83 @@ func (m *ProductModel) Delete(id int) error {
 	return err
 }
 
+// Import creates and updates the seller's products in a
+// single transaction, so either all of them are saved or
+// none are. A product with a ProductID of 0 is inserted
+// and given its new ID, and any other product updates the
+// row with its ProductID, which must be the seller's. It
+// returns models.ErrNoRecord, and saves none of them, if a
+// product to update is no longer one of the seller's.
+func (m *ProductModel) Import(sellerID string, products []*models.Product) error {
+	owned := `SELECT ProductID FROM Product WHERE SellerID = ? FOR UPDATE`
+	create := `INSERT INTO Product
+			(Name, Description, Keyword, CategoryID, Price, DiscountID, Inventory, SellerID)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?)`
+	update := `UPDATE Product
+			SET Name=?, Description=?, Keyword=?, Price=?, CategoryID=?, DiscountID=?, Inventory=?, Modified=NOW()
+			WHERE ProductID = ? AND SellerID = ?`
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// lock the seller's products so none of them can be
+	// deleted before they are updated. An update which
+	// changes nothing affects no rows, so the ProductIDs
+	// are checked here rather than by the rows affected.
+	rows, err := tx.Query(owned, sellerID)
+	if err != nil {
+		return err
+	}
+	ids := map[int]bool{}
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		ids[id] = true
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, p := range products {
+		if p.ProductID != 0 && !ids[p.ProductID] {
+			return models.ErrNoRecord
+		}
+		if p.ProductID == 0 {
+			result, err := tx.Exec(create, p.Name, p.Desc, p.Keyword, p.CategoryID, p.Price, p.DiscountID, p.Inventory, sellerID)
+			if err != nil {
+				return err
+			}
+			id, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			p.ProductID = int(id)
+			continue
+		}
+
+		_, err = tx.Exec(update, p.Name, p.Desc, p.Keyword, p.Price, p.CategoryID, p.DiscountID, p.Inventory, p.ProductID, sellerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // GetSellerProducts retrieves every row which
 // has the specified SellerID column value.
 func (m *ProductModel) GetSellerProducts(sellerID string) ([]*models.Product, error) {
 	stmt := `SELECT 
-				 ProductID, Name, Description, Price, CategoryID, DiscountID,
-				 Inventory, Created, SellerID, Rating, RatingNum, UnitSold
+				 ProductID, Name, Description, Keyword, Price, CategoryID, DiscountID,
+				 Inventory, Created, SellerID, Rating, RatingNum, UnitSold, Modified
 			FROM Product
 			WHERE SellerID=?`
 
@@ -144,10 +216,10 @@ func (m *ProductModel) GetSellerProducts(sellerID string) ([]*models.Product, er
 	for rows.Next() {
 		product := &models.Product{}
 		err = rows.Scan(
-			&product.ProductID, &product.Name, &product.Desc, &product.Price,
-			&product.CategoryID, &product.DiscountID, &product.Inventory,
+			&product.ProductID, &product.Name, &product.Desc, &product.Keyword,
+			&product.Price, &product.CategoryID, &product.DiscountID, &product.Inventory,
 			&product.Created, &product.SellerID, &product.Rating,
-			&product.RatingNum, &product.UnitSold,
+			&product.RatingNum, &product.UnitSold, &product.Modified,
 		)
 		if err != nil {
 			return nil, err
@@ -158,10 +230,24 @@ func (m *ProductModel) GetSellerProducts(sellerID string) ([]*models.Product, er
 }
 
 // GetSearchProducts retrieves the ProductID, Name
-// Description, and Keyword column values from every
-// row in the table.
+// Description, Keyword, UnitSold and Modified column
+// values from every row in the table.
 func (m *ProductModel) GetSearchProducts() ([]*models.Product, error) {
-	results, err := m.DB.Query("Select ProductID, Name, Description, Keyword FROM Product")
+	return m.searchProducts("Select ProductID, Name, Description, Keyword, UnitSold, Modified FROM Product")
+}
+
+// GetModifiedSearchProducts retrieves the same column
+// values as GetSearchProducts from the rows which were
+// modified at or after since.
+func (m *ProductModel) GetModifiedSearchProducts(since time.Time) ([]*models.Product, error) {
+	return m.searchProducts(`Select ProductID, Name, Description, Keyword, UnitSold, Modified FROM Product
+		WHERE Modified >= ?`, since)
+}
+
+// searchProducts runs a query selecting the columns
+// indexed by the search bar and scans the rows.
+func (m *ProductModel) searchProducts(query string, args ...interface{}) ([]*models.Product, error) {
+	results, err := m.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -170,14 +256,34 @@ func (m *ProductModel) GetSearchProducts() ([]*models.Product, error) {
 	products := []*models.Product{}
 	for results.Next() {
 		product := &models.Product{}
-		err = results.Scan(&product.ProductID, &product.Name, &product.Keyword, &product.Desc)
+		err = results.Scan(&product.ProductID, &product.Name, &product.Desc, &product.Keyword, &product.UnitSold, &product.Modified)
 		if err != nil {
-			panic(err.Error())
+			return nil, err
 		}
 		products = append(products, product)
 
 	}
-	return products, nil
+	return products, results.Err()
+}
+
+// GetProductIDs retrieves the ProductID column
+// value of every row in the table.
+func (m *ProductModel) GetProductIDs() ([]int, error) {
+	results, err := m.DB.Query("Select ProductID FROM Product")
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	ids := []int{}
+	for results.Next() {
+		var id int
+		if err = results.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, results.Err()
 }
 
 // GetSearchResults retrieves all the rows which
@@ -195,7 +301,7 @@ func (m *ProductModel) GetSearchResults(rankedIndex []int) ([]*models.Product, e
 		pIDs = pIDs + "," + i
 	}
 	pIDs = "(" + strings.TrimLeft(pIDs, ",") + ")"
-	stmt := `SELECT ProductID, Name, Description, Price, CategoryID, DiscountID, Inventory, Created, SellerID, Rating, RatingNum, UnitSold
+	stmt := `SELECT ProductID, Name, Description, Price, CategoryID, DiscountID, Inventory, Created, SellerID, Rating, RatingNum, UnitSold, Modified
 	FROM Product WHERE ProductID IN ` + pIDs
 
 	rows, err := m.DB.Query(stmt)
@@ -220,6 +326,7 @@ func (m *ProductModel) GetSearchResults(rankedIndex []int) ([]*models.Product, e
 			&product.Rating,
 			&product.RatingNum,
 			&product.UnitSold,
+			&product.Modified,
 		)
 		if err != nil {
 			return nil, err
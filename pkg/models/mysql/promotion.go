@@ -0,0 +1,173 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// PromotionModel wraps a sql.DB connection pool and keeps
+// the promotions sellers schedule on their products. The
+// times of a promotion are compared with the time passed
+// in by the caller rather than the database's clock.
+type PromotionModel struct {
+	DB *sql.DB
+}
+
+// Create inserts a new promotion of the product and returns
+// its ID. It returns models.ErrOverlapping if the product
+// has another promotion at any time between starts and ends,
+// and models.ErrNoRecord if there is no such product.
+func (m *PromotionModel) Create(productID int, name string, kind int, value float64, starts, ends time.Time, stockCap int) (int, error) {
+	lock := `SELECT ProductID FROM Product WHERE ProductID = ? FOR UPDATE`
+	overlap := `SELECT EXISTS (
+				SELECT 1 FROM Promotion WHERE ProductID = ? AND Starts < ? AND Ends > ?
+			)`
+	stmt := `INSERT INTO Promotion (ProductID, Name, Kind, Value, Starts, Ends, StockCap)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// the product is locked so promotions of it are created
+	// one at a time, and none can be created overlapping
+	// this one between the check and the insert
+	err = tx.QueryRow(lock, productID).Scan(&productID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, models.ErrNoRecord
+		}
+		return 0, err
+	}
+
+	var overlapping bool
+	err = tx.QueryRow(overlap, productID, ends, starts).Scan(&overlapping)
+	if err != nil {
+		return 0, err
+	}
+	if overlapping {
+		return 0, models.ErrOverlapping
+	}
+
+	result, err := tx.Exec(stmt, productID, name, kind, value, starts, ends, stockCap)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
+}
+
+// Delete deletes the promotion which has the specified
+// PromotionID, if it belongs to the product.
+func (m *PromotionModel) Delete(productID, promotionID int) error {
+	stmt := `DELETE FROM Promotion WHERE PromotionID = ? AND ProductID = ?`
+
+	result, err := m.DB.Exec(stmt, promotionID, productID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// GetProductPromotions retrieves every promotion of the
+// product, from the latest to start.
+func (m *PromotionModel) GetProductPromotions(productID int) ([]*models.Promotion, error) {
+	stmt := `SELECT PromotionID, ProductID, Name, Kind, Value, Starts, Ends, StockCap, Sold, Created
+			FROM Promotion
+			WHERE ProductID = ?
+			ORDER BY Starts DESC`
+
+	rows, err := m.DB.Query(stmt, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []*models.Promotion{}
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// GetActive retrieves the promotions of the products with
+// the specified ProductID column values which are running
+// at now and have units left, mapped by ProductID.
+func (m *PromotionModel) GetActive(productIDs []int, now time.Time) (map[int]*models.Promotion, error) {
+	promotions := map[int]*models.Promotion{}
+	if len(productIDs) == 0 {
+		return promotions, nil
+	}
+
+	args := make([]interface{}, 0, len(productIDs)+2)
+	for _, id := range productIDs {
+		args = append(args, id)
+	}
+	args = append(args, now, now)
+	stmt := `SELECT PromotionID, ProductID, Name, Kind, Value, Starts, Ends, StockCap, Sold, Created
+			FROM Promotion
+			WHERE ProductID IN (?` + strings.Repeat(",?", len(productIDs)-1) + `)
+				AND Starts <= ? AND Ends > ? AND (StockCap = 0 OR Sold < StockCap)`
+
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions[p.ProductID] = p
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// scanPromotion scans the columns of a promotion from the
+// current row, with its times in the server's time zone.
+func scanPromotion(rows *sql.Rows) (*models.Promotion, error) {
+	p := &models.Promotion{}
+	err := rows.Scan(
+		&p.PromotionID, &p.ProductID, &p.Name, &p.Kind, &p.Value,
+		&p.Starts, &p.Ends, &p.StockCap, &p.Sold, &p.Created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.Starts, p.Ends = p.Starts.Local(), p.Ends.Local()
+	return p, nil
+}
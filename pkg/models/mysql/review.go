@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// ReviewModel wraps a sql.DB connection pool and keeps
+// the reviews buyers leave for the products they ordered.
+// Adding a review recomputes the Rating and RatingNum of
+// the product from its reviews.
+type ReviewModel struct {
+	DB *sql.DB
+}
+
+// Create inserts a new review for the product of the order
+// which has the specified OrderID, and returns its ID. It
+// returns models.ErrNoRecord unless the order was placed
+// by the user and has been accepted, and
+// models.ErrDuplicateEntry if the order has been reviewed.
+// The review is only kept if the rating of the product is
+// recomputed with it.
+func (m *ReviewModel) Create(orderID int, userID string, rating int, text string) (int, error) {
+	stmt := `INSERT INTO Review (OrderID, ProductID, UserID, Rating, Text)
+			SELECT OrderID, ProductID, UserID, ?, ?
+			FROM Orders WHERE OrderID = ? AND UserID = ? AND Status = 1`
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(stmt, rating, text, orderID, userID)
+	if err != nil {
+		return 0, duplicateEntry(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rows == 0 {
+		return 0, models.ErrNoRecord
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	err = rate(tx, int(id))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
+}
+
+// rate sets the Rating of the product of the review to
+// the average rating of its reviews and its RatingNum to
+// the number of them.
+func rate(tx *sql.Tx, reviewID int) error {
+	stmt := `UPDATE Product
+			JOIN (
+				SELECT ProductID, AVG(Rating) AS Rating, COUNT(*) AS RatingNum
+				FROM Review
+				WHERE ProductID = (SELECT ProductID FROM Review WHERE ReviewID = ?)
+				GROUP BY ProductID
+			) AS Reviews ON Product.ProductID = Reviews.ProductID
+			SET Product.Rating = Reviews.Rating, Product.RatingNum = Reviews.RatingNum`
+
+	_, err := tx.Exec(stmt, reviewID)
+	return err
+}
+
+// Reply sets the seller's public reply to the review which
+// has the specified ReviewID. It returns
+// models.ErrNoRowsAffected unless the review is of one of
+// the seller's products.
+func (m *ReviewModel) Reply(reviewID int, sellerID, text string) error {
+	stmt := `UPDATE Review
+			JOIN Product ON Review.ProductID = Product.ProductID
+			SET Review.Reply = ?, Review.Replied = NOW()
+			WHERE Review.ReviewID = ? AND Product.SellerID = ?`
+
+	result, err := m.DB.Exec(stmt, text, reviewID, sellerID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// CountProductReviews returns the number of reviews
+// of the product.
+func (m *ReviewModel) CountProductReviews(productID int) (int, error) {
+	stmt := `SELECT COUNT(*) FROM Review WHERE ProductID = ?`
+
+	var n int
+	err := m.DB.QueryRow(stmt, productID).Scan(&n)
+	return n, err
+}
+
+// GetProductReviews retrieves limit reviews of the product,
+// from the latest, skipping the first offset of them.
+func (m *ReviewModel) GetProductReviews(productID, offset, limit int) ([]*models.Review, error) {
+	stmt := `SELECT
+				ReviewID, OrderID, ProductID, UserID, Rating, Text,
+				COALESCE(Reply, ''), COALESCE(Replied, Created), Created
+			FROM Review
+			WHERE ProductID = ?
+			ORDER BY Created DESC, ReviewID DESC
+			LIMIT ? OFFSET ?`
+
+	rows, err := m.DB.Query(stmt, productID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*models.Review{}
+	for rows.Next() {
+		r := &models.Review{}
+		err = rows.Scan(
+			&r.ReviewID, &r.OrderID, &r.ProductID, &r.UserID, &r.Rating,
+			&r.Text, &r.Reply, &r.Replied, &r.Created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
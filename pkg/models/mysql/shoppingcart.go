@@ -11,13 +11,14 @@ type CartModel struct {
 }
 
 // InsertItem inserts a new row into the table.
-func (m *CartModel) InsertItem(userID string, productID int) error {
+// The variantID is 0 for a product without variants.
+func (m *CartModel) InsertItem(userID string, productID, variantID int) error {
 	stmt :=
-		`INSERT INTO shoppingCart (UserID, ProductID, Qty) 
-		VALUES (?,?,1)
+		`INSERT INTO shoppingCart (UserID, ProductID, VariantID, Qty) 
+		VALUES (?,?,?,1)
 		ON DUPLICATE KEY UPDATE Qty = Qty+1;`
 
-	_, err := m.DB.Exec(stmt, userID, productID)
+	_, err := m.DB.Exec(stmt, userID, productID, variantID)
 	if err != nil {
 		return err
 	}
@@ -27,15 +28,19 @@ func (m *CartModel) InsertItem(userID string, productID int) error {
 
 // Get retrieves every row in the table that
 // has the specified UserID column value and
-// returns the result.
+// returns the result. The price, discount and
+// inventory of an item are those of its variant,
+// if it has one.
 func (m *CartModel) Get(userID string) ([]*models.CartItem, error) {
 	stmt :=
 		`SELECT
-			Product.Name, Product.Inventory, ShoppingCart.Qty,
-			Product.Price, Product.DiscountID, Product.SellerID,
-			Product.ProductID
+			Product.Name, COALESCE(ProductVariant.Inventory, Product.Inventory), ShoppingCart.Qty,
+			COALESCE(ProductVariant.Price, Product.Price),
+			COALESCE(ProductVariant.DiscountID, Product.DiscountID), Product.SellerID,
+			Product.ProductID, ShoppingCart.VariantID, COALESCE(ProductVariant.Name, '')
 		FROM ShoppingCart
 		LEFT JOIN Product ON ShoppingCart.ProductID = Product.ProductID
+		LEFT JOIN ProductVariant ON ShoppingCart.VariantID = ProductVariant.VariantID
 		WHERE  ShoppingCart.UserID = ?;`
 
 	rows, err := m.DB.Query(stmt, userID)
@@ -55,6 +60,8 @@ func (m *CartModel) Get(userID string) ([]*models.CartItem, error) {
 			&cartItem.Product.DiscountID,
 			&cartItem.Product.SellerID,
 			&cartItem.Product.ProductID,
+			&cartItem.Product.VariantID,
+			&cartItem.Product.Variant,
 		)
 		if err != nil {
 			return nil, err
@@ -67,13 +74,13 @@ func (m *CartModel) Get(userID string) ([]*models.CartItem, error) {
 
 // Update updates the Qty column value in the
 // table for the row that has the specified
-// UserID & ProductID column values.
-func (m *CartModel) Update(quantity, productid int, userID string) error {
+// UserID, ProductID & VariantID column values.
+func (m *CartModel) Update(quantity, productid, variantID int, userID string) error {
 	stmt := `UPDATE ShoppingCart 
 			 SET Qty= ? 
-			 WHERE (UserID = ? AND ProductID = ?)`
+			 WHERE (UserID = ? AND ProductID = ? AND VariantID = ?)`
 
-	res, err := m.DB.Exec(stmt, quantity, userID, productid)
+	res, err := m.DB.Exec(stmt, quantity, userID, productid, variantID)
 	if err != nil {
 		return err
 	}
@@ -86,13 +93,13 @@ func (m *CartModel) Update(quantity, productid int, userID string) error {
 }
 
 // DeleteItem deletes a row in the table that
-// has the specified UserID & ProductID
-// column value.
-func (m *CartModel) DeleteItem(userID string, productID int) error {
+// has the specified UserID, ProductID &
+// VariantID column value.
+func (m *CartModel) DeleteItem(userID string, productID, variantID int) error {
 	stmt := `Delete FROM shoppingCart 
-			 WHERE (UserID = ? AND ProductID = ?)`
+			 WHERE (UserID = ? AND ProductID = ? AND VariantID = ?)`
 
-	_, err := m.DB.Exec(stmt, userID, productID)
+	_, err := m.DB.Exec(stmt, userID, productID, variantID)
 	if err != nil {
 		return err
 	}
@@ -118,18 +125,23 @@ func (m *CartModel) DeleteAll(userID string) error {
 // for every row that has the specified UserID
 // column value is less than the iventory column
 // value in the product table via the ProductID
-// foreign key.
+// foreign key, or in the productvariant table
+// for an item with a variant. An item whose
+// variant has been deleted is invalid.
 func (m *CartModel) CheckOut(userid string) ([]*models.CartItem, error) {
 	stmt :=
 		`SELECT
 			shoppingcart.UserID,
 			shoppingcart.ProductID,
 			product.Name,
-			product.DiscountID,
-			product.Inventory,
-			product.Price,
+			COALESCE(productvariant.DiscountID, product.DiscountID),
+			COALESCE(productvariant.Inventory, product.Inventory),
+			COALESCE(productvariant.Price, product.Price),
 			shoppingcart.Qty,
-			CASE WHEN shoppingcart.Qty < product.Inventory
+			shoppingcart.VariantID,
+			COALESCE(productvariant.Name, ''),
+			CASE WHEN shoppingcart.Qty < COALESCE(productvariant.Inventory, product.Inventory)
+				AND (shoppingcart.VariantID = 0 OR productvariant.VariantID IS NOT NULL)
 				THEN '0'
 				ELSE '1'
 			END
@@ -137,6 +149,8 @@ func (m *CartModel) CheckOut(userid string) ([]*models.CartItem, error) {
 		FROM shoppingcart
 		LEFT JOIN product ON
 			shoppingcart.ProductID = product.ProductID
+		LEFT JOIN productvariant ON
+			shoppingcart.VariantID = productvariant.VariantID
 		WHERE shoppingcart.UserID = ?;`
 
 	rows, err := m.DB.Query(stmt, userid)
@@ -155,6 +169,8 @@ func (m *CartModel) CheckOut(userid string) ([]*models.CartItem, error) {
 			&i.Product.Inventory,
 			&i.Product.Price,
 			&i.Qty,
+			&i.Product.VariantID,
+			&i.Product.Variant,
 			&i.Invalid)
 		if err != nil {
 			return nil, err
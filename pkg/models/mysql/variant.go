@@ -0,0 +1,195 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ProjectGoLive/pkg/models"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// VariantModel wraps a sql.DB connection pool and keeps
+// the variants of the products. Every change to the
+// variants of a product also rolls their price, discount
+// and inventory up into the product's row.
+type VariantModel struct {
+	DB *sql.DB
+}
+
+// Create inserts a new variant of the product and returns
+// its ID. It returns models.ErrDuplicateEntry if the
+// product already has a variant with the name.
+func (m *VariantModel) Create(productID int, name string, price float64, discID, inventory int) (int, error) {
+	stmt := `INSERT INTO ProductVariant (ProductID, Name, Price, DiscountID, Inventory)
+			VALUES (?, ?, ?, ?, ?)`
+
+	result, err := m.DB.Exec(stmt, productID, name, price, discID, inventory)
+	if err != nil {
+		return 0, duplicateEntry(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), m.RollUp(productID)
+}
+
+// Update edits the variant which has the specified
+// VariantID, if it belongs to the product.
+func (m *VariantModel) Update(productID, variantID int, name string, price float64, discID, inventory int) error {
+	stmt := `UPDATE ProductVariant
+			SET Name = ?, Price = ?, DiscountID = ?, Inventory = ?, Modified = NOW()
+			WHERE VariantID = ? AND ProductID = ?`
+
+	_, err := m.DB.Exec(stmt, name, price, discID, inventory, variantID, productID)
+	if err != nil {
+		return duplicateEntry(err)
+	}
+
+	return m.RollUp(productID)
+}
+
+// Delete deletes the variant which has the specified
+// VariantID, if it belongs to the product.
+func (m *VariantModel) Delete(productID, variantID int) error {
+	stmt := `DELETE FROM ProductVariant WHERE VariantID = ? AND ProductID = ?`
+
+	result, err := m.DB.Exec(stmt, variantID, productID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrNoRowsAffected
+	}
+
+	return m.RollUp(productID)
+}
+
+// RollUp sets the Price and DiscountID of the product to
+// those of its variant with the lowest discounted price, so
+// the product is listed at a price one of its variants sells
+// at, and its Inventory to the total inventory of the
+// variants. A product without variants is left as it is.
+func (m *VariantModel) RollUp(productID int) error {
+	stmt := `UPDATE Product
+			JOIN (
+				SELECT ProductID, SUM(Inventory) AS Inventory
+				FROM ProductVariant WHERE ProductID = ? GROUP BY ProductID
+			) AS Variants ON Product.ProductID = Variants.ProductID
+			JOIN (
+				SELECT ProductID, Price, DiscountID
+				FROM ProductVariant WHERE ProductID = ?
+				ORDER BY Price * ` + discMultiplier("DiscountID") + `, VariantID LIMIT 1
+			) AS Cheapest ON Product.ProductID = Cheapest.ProductID
+			SET Product.Price = Cheapest.Price, Product.DiscountID = Cheapest.DiscountID,
+				Product.Inventory = Variants.Inventory, Product.Modified = NOW()`
+
+	_, err := m.DB.Exec(stmt, productID, productID)
+	return err
+}
+
+// discMultiplier returns a SQL expression for the
+// multiplier in models.DiscMultiplier of the DiscountID
+// in the column.
+func discMultiplier(column string) string {
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for id, multiplier := range models.DiscMultiplier {
+		b.WriteString(fmt.Sprintf(" WHEN %d THEN %g", id, multiplier))
+	}
+	b.WriteString(" ELSE 1 END")
+	return b.String()
+}
+
+// Get retrieves the variant which has the specified
+// VariantID, if it belongs to the product.
+func (m *VariantModel) Get(productID, variantID int) (*models.Variant, error) {
+	stmt := `SELECT VariantID, ProductID, Name, Price, DiscountID, Inventory, UnitSold, Created, Modified
+			FROM ProductVariant WHERE VariantID = ? AND ProductID = ?`
+
+	v := &models.Variant{}
+	err := m.DB.QueryRow(stmt, variantID, productID).Scan(
+		&v.VariantID, &v.ProductID, &v.Name, &v.Price, &v.DiscountID,
+		&v.Inventory, &v.UnitSold, &v.Created, &v.Modified,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrNoRecord
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// GetProductVariants retrieves the variants of the
+// product, from the cheapest.
+func (m *VariantModel) GetProductVariants(productID int) ([]*models.Variant, error) {
+	variants, err := m.GetVariants([]int{productID})
+	if err != nil {
+		return nil, err
+	}
+	return variants[productID], nil
+}
+
+// GetVariants retrieves the variants of the products with
+// the specified ProductID column values, mapped by
+// ProductID, from the cheapest.
+func (m *VariantModel) GetVariants(productIDs []int) (map[int][]*models.Variant, error) {
+	variants := map[int][]*models.Variant{}
+	if len(productIDs) == 0 {
+		return variants, nil
+	}
+
+	args := make([]interface{}, len(productIDs))
+	for i, id := range productIDs {
+		args[i] = id
+	}
+	stmt := `SELECT VariantID, ProductID, Name, Price, DiscountID, Inventory, UnitSold, Created, Modified
+			FROM ProductVariant
+			WHERE ProductID IN (?` + strings.Repeat(",?", len(productIDs)-1) + `)
+			ORDER BY ProductID, Price, VariantID`
+
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		v := &models.Variant{}
+		err = rows.Scan(
+			&v.VariantID, &v.ProductID, &v.Name, &v.Price, &v.DiscountID,
+			&v.Inventory, &v.UnitSold, &v.Created, &v.Modified,
+		)
+		if err != nil {
+			return nil, err
+		}
+		variants[v.ProductID] = append(variants[v.ProductID], v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// duplicateEntry returns models.ErrDuplicateEntry if
+// err is a MySQL duplicate key error, or else err.
+func duplicateEntry(err error) error {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == 1062 {
+		return models.ErrDuplicateEntry
+	}
+	return err
+}
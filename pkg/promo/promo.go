@@ -0,0 +1,119 @@
+// Package promo works out the price of a product under
+// its promotion. Promotions are scheduled ahead of time,
+// so whether one applies is decided every time a price
+// is read rather than stored with the product.
+package promo
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// FlashSale is the longest a promotion can run for and
+// still be a flash sale, which is shown with a countdown.
+const FlashSale = 24 * time.Hour
+
+// Active reports whether the promotion applies at now: it
+// has started, has not ended and has units left at its
+// price.
+func Active(p *models.Promotion, now time.Time) bool {
+	if p == nil {
+		return false
+	}
+	return !now.Before(p.Starts) && now.Before(p.Ends) && Left(p) != 0
+}
+
+// Left returns the number of units left at the price of
+// the promotion, or -1 if it has no stock cap.
+func Left(p *models.Promotion) int {
+	if p.StockCap == 0 {
+		return -1
+	}
+	if p.Sold >= p.StockCap {
+		return 0
+	}
+	return p.StockCap - p.Sold
+}
+
+// Price returns the unit price, rounded to the cent, of a
+// product with the price and DiscountID at now. The promotion
+// takes the place of the standing discount if it is active
+// and gives a lower price; the two are never combined. An
+// amount off which is not less than the price, such as of
+// a variant repriced since the promotion was scheduled, is
+// not applied rather than giving the product away.
+func Price(price float64, discountID int, p *models.Promotion, now time.Time) float64 {
+	return math.Round(Exact(price, discountID, p, now)*100) / 100
+}
+
+// Exact returns the same unit price as Price without
+// rounding it, for totals which are rounded only once.
+func Exact(price float64, discountID int, p *models.Promotion, now time.Time) float64 {
+	final := price
+	if discountID > 0 && discountID < len(models.DiscMultiplier) {
+		final = price * models.DiscMultiplier[discountID]
+	}
+
+	if Active(p, now) {
+		sale := final
+		switch {
+		case p.Kind != models.PromotionAmount:
+			sale = price * (1 - p.Value/100)
+		case p.Value < price:
+			sale = price - p.Value
+		}
+		if sale < final {
+			final = sale
+		}
+	}
+
+	return final
+}
+
+// Flash reports whether the promotion is a flash sale.
+func Flash(p *models.Promotion) bool {
+	return p.Ends.Sub(p.Starts) <= FlashSale
+}
+
+// Label describes the discount of the promotion, such as
+// "15% off" or "$2.50 off".
+func Label(p *models.Promotion) string {
+	if p.Kind == models.PromotionAmount {
+		return fmt.Sprintf("$%.2f off", p.Value)
+	}
+	return fmt.Sprintf("%g%% off", p.Value)
+}
+
+// Countdown returns the time left until the promotion
+// ends at now, as hours, minutes and seconds, preceded by
+// the days if there is more than a day left.
+func Countdown(p *models.Promotion, now time.Time) string {
+	d := p.Ends.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+	s := int(d / time.Second)
+	days, h, m := s/86400, s/3600%24, s/60%60
+	s %= 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %02d:%02d:%02d", days, h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+}
+
+// Status describes where the promotion is at now: it is
+// "Upcoming", "Running", "Sold out" or "Ended".
+func Status(p *models.Promotion, now time.Time) string {
+	switch {
+	case now.Before(p.Starts):
+		return "Upcoming"
+	case !now.Before(p.Ends):
+		return "Ended"
+	case Left(p) == 0:
+		return "Sold out"
+	}
+	return "Running"
+}
@@ -0,0 +1,148 @@
+package promo
+
+import (
+	"testing"
+	"time"
+
+	"ProjectGoLive/pkg/models"
+)
+
+var now = time.Date(2021, 5, 14, 12, 0, 0, 0, time.UTC)
+
+func promotion(kind int, value float64, starts, ends time.Duration, stockCap, sold int) *models.Promotion {
+	return &models.Promotion{
+		Kind:     kind,
+		Value:    value,
+		Starts:   now.Add(starts),
+		Ends:     now.Add(ends),
+		StockCap: stockCap,
+		Sold:     sold,
+	}
+}
+
+func Test_Active(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *models.Promotion
+		want bool
+	}{
+		{name: "No promotion", p: nil, want: false},
+		{name: "Running", p: promotion(models.PromotionPercent, 10, -time.Hour, time.Hour, 0, 0), want: true},
+		{name: "Starts now", p: promotion(models.PromotionPercent, 10, 0, time.Hour, 0, 0), want: true},
+		{name: "Ends now", p: promotion(models.PromotionPercent, 10, -time.Hour, 0, 0, 0), want: false},
+		{name: "Upcoming", p: promotion(models.PromotionPercent, 10, time.Minute, time.Hour, 0, 0), want: false},
+		{name: "Units left", p: promotion(models.PromotionPercent, 10, -time.Hour, time.Hour, 5, 4), want: true},
+		{name: "Sold out", p: promotion(models.PromotionPercent, 10, -time.Hour, time.Hour, 5, 5), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Active(tt.p, now); got != tt.want {
+				t.Errorf("Active() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Price(t *testing.T) {
+	tests := []struct {
+		name       string
+		price      float64
+		discountID int
+		p          *models.Promotion
+		want       float64
+	}{
+		{name: "No discount", price: 10, want: 10},
+		{name: "Standing discount", price: 10, discountID: 2, want: 9},
+		{name: "Percent off", price: 10, p: promotion(models.PromotionPercent, 30, -time.Hour, time.Hour, 0, 0), want: 7},
+		{name: "Amount off", price: 10, p: promotion(models.PromotionAmount, 2.5, -time.Hour, time.Hour, 0, 0), want: 7.5},
+		{name: "Amount off more than price", price: 2, p: promotion(models.PromotionAmount, 5, -time.Hour, time.Hour, 0, 0), want: 2},
+		{name: "Amount off the whole price", price: 5, discountID: 1, p: promotion(models.PromotionAmount, 5, -time.Hour, time.Hour, 0, 0), want: 4.75},
+		{name: "Better standing discount", price: 10, discountID: 5, p: promotion(models.PromotionPercent, 10, -time.Hour, time.Hour, 0, 0), want: 7.5},
+		{name: "Better promotion", price: 10, discountID: 1, p: promotion(models.PromotionPercent, 10, -time.Hour, time.Hour, 0, 0), want: 9},
+		{name: "Ended promotion", price: 10, discountID: 1, p: promotion(models.PromotionPercent, 50, -2*time.Hour, -time.Hour, 0, 0), want: 9.5},
+		{name: "Sold out promotion", price: 10, p: promotion(models.PromotionPercent, 50, -time.Hour, time.Hour, 3, 3), want: 10},
+		{name: "Rounded to cents", price: 3.33, p: promotion(models.PromotionPercent, 15, -time.Hour, time.Hour, 0, 0), want: 2.83},
+		{name: "Invalid discount", price: 10, discountID: 99, want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Price(tt.price, tt.discountID, tt.p, now); got != tt.want {
+				t.Errorf("Price() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Exact(t *testing.T) {
+	if got := Exact(3.33, 1, nil, now); got != 3.33*0.95 {
+		t.Errorf("Exact() = %v, want %v", got, 3.33*0.95)
+	}
+}
+
+func Test_Left(t *testing.T) {
+	if got := Left(promotion(models.PromotionPercent, 10, 0, time.Hour, 0, 7)); got != -1 {
+		t.Errorf("Left() without a cap = %d, want -1", got)
+	}
+	if got := Left(promotion(models.PromotionPercent, 10, 0, time.Hour, 10, 7)); got != 3 {
+		t.Errorf("Left() = %d, want 3", got)
+	}
+	if got := Left(promotion(models.PromotionPercent, 10, 0, time.Hour, 10, 12)); got != 0 {
+		t.Errorf("Left() oversold = %d, want 0", got)
+	}
+}
+
+func Test_Flash(t *testing.T) {
+	if !Flash(promotion(models.PromotionPercent, 10, -time.Hour, 23*time.Hour, 0, 0)) {
+		t.Error("a 24 hour promotion is not a flash sale")
+	}
+	if Flash(promotion(models.PromotionPercent, 10, -time.Hour, 24*time.Hour, 0, 0)) {
+		t.Error("a 25 hour promotion is a flash sale")
+	}
+}
+
+func Test_Label(t *testing.T) {
+	if got := Label(promotion(models.PromotionPercent, 12.5, 0, 0, 0, 0)); got != "12.5% off" {
+		t.Errorf("Label() = %q", got)
+	}
+	if got := Label(promotion(models.PromotionAmount, 2, 0, 0, 0, 0)); got != "$2.00 off" {
+		t.Errorf("Label() = %q", got)
+	}
+}
+
+func Test_Countdown(t *testing.T) {
+	tests := []struct {
+		ends time.Duration
+		want string
+	}{
+		{ends: 2*time.Hour + 5*time.Minute + 9*time.Second + 500*time.Millisecond, want: "02:05:09"},
+		{ends: 49 * time.Hour, want: "2d 01:00:00"},
+		{ends: -time.Minute, want: "00:00:00"},
+	}
+
+	for _, tt := range tests {
+		p := promotion(models.PromotionPercent, 10, -time.Hour, tt.ends, 0, 0)
+		if got := Countdown(p, now); got != tt.want {
+			t.Errorf("Countdown() with %v left = %q, want %q", tt.ends, got, tt.want)
+		}
+	}
+}
+
+func Test_Status(t *testing.T) {
+	tests := []struct {
+		p    *models.Promotion
+		want string
+	}{
+		{p: promotion(models.PromotionPercent, 10, time.Hour, 2*time.Hour, 0, 0), want: "Upcoming"},
+		{p: promotion(models.PromotionPercent, 10, -time.Hour, time.Hour, 0, 0), want: "Running"},
+		{p: promotion(models.PromotionPercent, 10, -time.Hour, time.Hour, 2, 2), want: "Sold out"},
+		{p: promotion(models.PromotionPercent, 10, -2*time.Hour, -time.Hour, 2, 2), want: "Ended"},
+	}
+
+	for _, tt := range tests {
+		if got := Status(tt.p, now); got != tt.want {
+			t.Errorf("Status() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	snowballeng "github.com/kljensen/snowball/english"
+)
+
+// Token is a term found in a text. Start and End are the
+// byte offsets of the part of the text the term came from,
+// so matches can be highlighted.
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Analyzer turns text into the terms which are indexed for
+// a product or looked up for a search.
+type Analyzer interface {
+	Analyze(text string) []Token
+}
+
+// Tokenizer breaks text down into tokens.
+type Tokenizer func(text string) []Token
+
+// TokenFilter transforms a list of tokens. A filter may
+// drop tokens but must keep the rest in order.
+type TokenFilter func(tokens []Token) []Token
+
+// Pipeline is an Analyzer which breaks the text down with
+// its Tokenizer and passes the tokens through its Filters
+// in order.
+type Pipeline struct {
+	Tokenizer Tokenizer
+	Filters   []TokenFilter
+}
+
+// Analyze runs the text through the pipeline.
+func (p Pipeline) Analyze(text string) []Token {
+	tokens := p.Tokenizer(text)
+	for _, filter := range p.Filters {
+		tokens = filter(tokens)
+	}
+	return tokens
+}
+
+// analyzers provided for the languages used by sellers
+var (
+	// English removes English stopwords and stems with
+	// the English snowball stemmer.
+	English Analyzer = Pipeline{
+		Tokenizer: WordTokenizer,
+		Filters:   []TokenFilter{LowercaseFilter, StopwordFilter("en"), EnglishStemmer},
+	}
+
+	// Malay removes Malay stopwords and strips common
+	// Malay affixes. The Indonesian stopword list is
+	// used as the two languages share most stopwords.
+	Malay Analyzer = Pipeline{
+		Tokenizer: WordTokenizer,
+		Filters:   []TokenFilter{LowercaseFilter, StopwordFilter("id"), MalayStemmer},
+	}
+
+	// Chinese breaks runs of Chinese characters into
+	// bigrams and treats any other words as English.
+	Chinese Analyzer = Pipeline{
+		Tokenizer: CJKBigramTokenizer,
+		Filters:   []TokenFilter{LowercaseFilter, StopwordFilter("en"), EnglishStemmer},
+	}
+)
+
+// FieldAnalyzers holds the analyzer used for each field
+// of a product.
+type FieldAnalyzers struct {
+	Name    Analyzer
+	Desc    Analyzer
+	Keyword Analyzer
+}
+
+// AllFields returns FieldAnalyzers which use a for every
+// field.
+func AllFields(a Analyzer) FieldAnalyzers {
+	return FieldAnalyzers{Name: a, Desc: a, Keyword: a}
+}
+
+// analyzer returns the analyzer for the field.
+func (f FieldAnalyzers) analyzer(field int) Analyzer {
+	switch field {
+	case fieldName:
+		return f.Name
+	case fieldDesc:
+		return f.Desc
+	default:
+		return f.Keyword
+	}
+}
+
+// Language holds the analyzers used for products written
+// in the language with the ISO 639-1 code Code.
+type Language struct {
+	Code   string
+	Fields FieldAnalyzers
+}
+
+// DefaultLanguages are the languages the index analyzes
+// products in. The first language is used for products
+// whose language is not listed.
+var DefaultLanguages = []Language{
+	{Code: "en", Fields: AllFields(English)},
+	{Code: "ms", Fields: AllFields(Malay)},
+	{Code: "zh", Fields: AllFields(Chinese)},
+}
+
+// SetLanguages changes the languages products are analyzed
+// in. Products already in the index are not analyzed again
+// until they are updated or the index is rebuilt.
+func (idx *IndexSlice) SetLanguages(languages []Language) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.languages = languages
+	idx.compileSynonyms()
+}
+
+// DetectLanguage guesses the language of the text. Text
+// with any Chinese, Japanese or Korean characters is taken
+// to be Chinese; otherwise the text is Malay if it has
+// more Malay words than English stopwords, or English.
+func DetectLanguage(text string) string {
+	for _, r := range text {
+		if isCJK(r) {
+			return "zh"
+		}
+	}
+
+	malay, english := 0, 0
+	for _, token := range LowercaseFilter(WordTokenizer(text)) {
+		if malayWords[token.Term] || isStopword("id", token.Term) {
+			malay++
+		}
+		if isStopword("en", token.Term) {
+			english++
+		}
+	}
+	if malay > english {
+		return "ms"
+	}
+	return "en"
+}
+
+// WordTokenizer breaks text down into words by splitting
+// on any character that is not a letter or a number.
+func WordTokenizer(text string) []Token {
+	tokens := []Token{}
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, Token{text[start:i], start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{text[start:], start, len(text)})
+	}
+	return tokens
+}
+
+// CJKBigramTokenizer breaks text down into words like
+// WordTokenizer, then breaks runs of Chinese, Japanese
+// and Korean characters, which are not separated by
+// spaces, into overlapping pairs of characters.
+func CJKBigramTokenizer(text string) []Token {
+	tokens := []Token{}
+	for _, word := range WordTokenizer(text) {
+		// offsets holds the offset of every rune in
+		// the word, followed by the end of the word
+		rs := []rune(word.Term)
+		offsets := make([]int, 0, len(rs)+1)
+		for i := range word.Term {
+			offsets = append(offsets, word.Start+i)
+		}
+		offsets = append(offsets, word.End)
+
+		for i := 0; i < len(rs); {
+			j := i + 1
+			for j < len(rs) && isCJK(rs[j]) == isCJK(rs[i]) {
+				j++
+			}
+			if !isCJK(rs[i]) || j-i == 1 {
+				tokens = append(tokens, Token{string(rs[i:j]), offsets[i], offsets[j]})
+			} else {
+				for k := i; k+1 < j; k++ {
+					tokens = append(tokens, Token{string(rs[k : k+2]), offsets[k], offsets[k+2]})
+				}
+			}
+			i = j
+		}
+	}
+	return tokens
+}
+
+// termsOf returns the terms of the tokens.
+func termsOf(tokens []Token) []string {
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		terms[i] = token.Term
+	}
+	return terms
+}
+
+// isCJK reports whether r is a Chinese, Japanese or
+// Korean character.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// LowercaseFilter converts every token to lower case
+// so the search is not case-sensitive.
+func LowercaseFilter(tokens []Token) []Token {
+	r := make([]Token, len(tokens))
+	for i, token := range tokens {
+		r[i] = Token{strings.ToLower(token.Term), token.Start, token.End}
+	}
+	return r
+}
+
+// StopwordFilter returns a filter which removes the
+// stopwords of the language with the ISO 639-1 code lang
+// to reduce false positives in the search result. The
+// tokens must already be in lower case.
+func StopwordFilter(lang string) TokenFilter {
+	return func(tokens []Token) []Token {
+		r := make([]Token, 0, len(tokens))
+		for _, token := range tokens {
+			if !isStopword(lang, token.Term) {
+				r = append(r, token)
+			}
+		}
+		return r
+	}
+}
+
+// isStopword reports whether the lowercase token is a
+// stopword in the language.
+func isStopword(lang, token string) bool {
+	return stopwords[lang][token]
+}
+
+// hasLetter reports whether the token has any letter in it.
+func hasLetter(token string) bool {
+	return strings.IndexFunc(token, unicode.IsLetter) >= 0
+}
+
+// EnglishStemmer stems every token so relevant results
+// that do not match the search term exactly are included
+// in the results.
+func EnglishStemmer(tokens []Token) []Token {
+	r := make([]Token, len(tokens))
+	for i, token := range tokens {
+		r[i] = Token{snowballeng.Stem(token.Term, false), token.Start, token.End}
+	}
+	return r
+}
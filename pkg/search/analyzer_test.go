@@ -0,0 +1,150 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"reflect"
+	"testing"
+)
+
+func Test_CJKBigramTokenizer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "Latin words", text: "Chye Sim", want: []string{"Chye", "Sim"}},
+		{name: "Two characters", text: "菜心", want: []string{"菜心"}},
+		{name: "Bigrams", text: "上海白菜", want: []string{"上海", "海白", "白菜"}},
+		{name: "Single character", text: "鱼 ball", want: []string{"鱼", "ball"}},
+		{name: "Mixed word", text: "Chye Sim菜心", want: []string{"Chye", "Sim", "菜心"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := termsOf(CJKBigramTokenizer(tt.text)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CJKBigramTokenizer(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_StopwordFilter(t *testing.T) {
+	tests := []struct {
+		lang string
+		text string
+		want []string
+	}{
+		{lang: "en", text: "Rice for the Family", want: []string{"rice", "family"}},
+		{lang: "id", text: "Beras dengan yang keluarga", want: []string{"beras", "keluarga"}},
+		{lang: "en", text: "A1 sauce 2 pack", want: []string{"a1", "sauce", "2", "pack"}},
+		{lang: "xx", text: "the rice", want: []string{"the", "rice"}},
+	}
+
+	for _, tt := range tests {
+		got := termsOf(StopwordFilter(tt.lang)(LowercaseFilter(WordTokenizer(tt.text))))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("StopwordFilter(%q)(%q) = %q, want %q", tt.lang, tt.text, got, tt.want)
+		}
+	}
+}
+
+func Test_stemMalay(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "sayuran", want: "sayur"},
+		{word: "masakan", want: "masak"},
+		{word: "dimasak", want: "masak"},
+		{word: "bertelur", want: "telur"},
+		{word: "menyambal", want: "sambal"},
+		{word: "ikannya", want: "ikan"},
+		{word: "ikan", want: "ikan"},
+		{word: "durian", want: "durian"},
+		{word: "beras", want: "beras"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := stemMalay(tt.word); got != tt.want {
+				t.Errorf("stemMalay(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Frozen Chicken Thigh", want: "en"},
+		{text: "Ikan Bilis Kering", want: "ms"},
+		{text: "Sayur Campur dengan Udang", want: "ms"},
+		{text: "Dried anchovies for the sambal and the nasi lemak", want: "en"},
+		{text: "Chye Sim 菜心", want: "zh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_SearchLanguages(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Ikan Bilis Kering", Desc: "Ikan bilis untuk sambal.", Keyword: "ikan"},
+		{ProductID: 2, Name: "Sayuran Campur", Desc: "Sayur segar.", Keyword: "sayur"},
+		{ProductID: 3, Name: "上海白菜", Desc: "新鲜白菜", Keyword: "vegetables"},
+		{ProductID: 4, Name: "Chye Sim 菜心", Desc: "Fresh leafy greens.", Keyword: "vegetables"},
+		{ProductID: 5, Name: "Frozen Chicken Wings", Desc: "Chicken wings.", Keyword: "chicken"},
+	})
+
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{name: "Malay words are not stemmed as English", text: "ikan bilis", want: []int{1}},
+		{name: "Malay suffix", text: "sayur", want: []int{2}},
+		{name: "Chinese word inside a name", text: "白菜", want: []int{3}},
+		{name: "Chinese phrase", text: "菜心", want: []int{4}},
+		{name: "Latin words in a Chinese product", text: "greens", want: []int{4}},
+		{name: "English stemming", text: "chickens", want: []int{5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := idx.Search(tt.text)
+			if !sameIDs(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_SetLanguages(t *testing.T) {
+	// keywords are matched whole, without stemming
+	keywords := Pipeline{Tokenizer: WordTokenizer, Filters: []TokenFilter{LowercaseFilter}}
+	idx := NewIndexSlice()
+	idx.SetLanguages([]Language{
+		{Code: "en", Fields: FieldAnalyzers{Name: English, Desc: English, Keyword: keywords}},
+	})
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Kaya Toast", Keyword: "breakfast toasts"},
+	})
+
+	if got, _ := idx.Search("toasts"); !sameIDs(got, []int{1}) {
+		t.Errorf("Search(%q) = %v, want %v", "toasts", got, []int{1})
+	}
+	idx.mu.RLock()
+	_, stemmed := idx.maps[fieldKeyword]["toast"]
+	idx.mu.RUnlock()
+	if stemmed {
+		t.Errorf("keyword was stemmed by the keyword analyzer")
+	}
+}
@@ -0,0 +1,79 @@
+package search
+
+import "math"
+
+// Boosts holds the weight given to a search term found in
+// each field of a product. A term found in a field with a
+// higher boost contributes more to the relevance score.
+type Boosts struct {
+	Name    float64
+	Desc    float64
+	Keyword float64
+}
+
+// BM25 holds the parameters of the Okapi BM25 ranking
+// function. K1 controls how quickly repeated occurrences
+// of a term stop adding to the score and B controls how
+// much a long field is penalised against a short one.
+type BM25 struct {
+	K1     float64
+	B      float64
+	Boosts Boosts
+}
+
+// DefaultBM25 keeps the relative field weights the search
+// bar has always used: keywords above names above
+// descriptions.
+var DefaultBM25 = BM25{
+	K1:     1.2,
+	B:      0.75,
+	Boosts: Boosts{Name: 2, Desc: 1, Keyword: 4},
+}
+
+// SetBM25 changes the parameters used to score matches.
+func (idx *IndexSlice) SetBM25(bm25 BM25) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.bm25 = bm25
+}
+
+// boost returns the boost for the field.
+func (b Boosts) boost(field int) float64 {
+	switch field {
+	case fieldName:
+		return b.Name
+	case fieldDesc:
+		return b.Desc
+	default:
+		return b.Keyword
+	}
+}
+
+// score adds the BM25 score of a term in one field to
+// the products in its posting list, multiplied by the
+// field boost and by weight. The caller must hold the
+// read lock.
+func (idx *IndexSlice) score(scores map[int]float64, field int, term string, weight float64) {
+	postings := idx.maps[field][term]
+	if len(postings) == 0 {
+		return
+	}
+
+	n := float64(len(idx.docs))
+	df := float64(len(postings))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	avgLength := float64(idx.length[field]) / n
+	k1, b := idx.bm25.K1, idx.bm25.B
+	boost := idx.bm25.Boosts.boost(field) * weight
+
+	for _, p := range postings {
+		tf := float64(len(p.pos))
+		norm := 1 - b
+		if avgLength > 0 {
+			norm += b * float64(idx.docs[p.id].length[field]) / avgLength
+		}
+		scores[p.id] += boost * idf * tf * (k1 + 1) / (tf + k1*norm)
+	}
+}
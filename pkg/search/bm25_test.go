@@ -0,0 +1,49 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"testing"
+)
+
+func Test_IndexSlice_SearchBM25(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Jasmine Rice", Desc: "Fragrant long grain.", Keyword: "staples"},
+		{ProductID: 2, Name: "Hainanese Chicken", Desc: "Poached chicken with ginger, garlic, chilli sauce and dark soy, best eaten with rice.", Keyword: "chicken"},
+		{ProductID: 3, Name: "Brown Rice Rice Crackers", Desc: "Crackers.", Keyword: "snacks"},
+		{ProductID: 4, Name: "Ginger", Desc: "Fresh ginger.", Keyword: "vegetables"},
+	})
+
+	_, scores := idx.Search("rice")
+
+	// a short name is a better match than a long description
+	if scores[1] <= scores[2] {
+		t.Errorf("name match scored %v, description match scored %v", scores[1], scores[2])
+	}
+	// repeating a term adds to the score, less than linearly
+	if scores[3] <= scores[1] || scores[3] >= 2*scores[1] {
+		t.Errorf("repeated term scored %v, single term scored %v", scores[3], scores[1])
+	}
+
+	// the boosts are configurable
+	idx.SetBM25(BM25{K1: 1.2, B: 0.75, Boosts: Boosts{Name: 0, Desc: 1, Keyword: 0}})
+	_, scores = idx.Search("rice")
+	if scores[2] <= scores[1] {
+		t.Errorf("with only descriptions boosted, name match scored %v, description match scored %v", scores[1], scores[2])
+	}
+}
+
+func Test_IndexSlice_SearchIDF(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Fresh Durian"},
+		{ProductID: 2, Name: "Fresh Milk"},
+		{ProductID: 3, Name: "Fresh Eggs"},
+	})
+
+	// a rare term counts for more than a common one
+	_, scores := idx.Search("fresh durian")
+	if scores[1] <= 2*scores[2] {
+		t.Errorf("rare term scored %v, common term scored %v", scores[1], scores[2])
+	}
+}
@@ -0,0 +1,170 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggestion is a completion for a partially typed search.
+// ProductID is set when the suggestion is a product name
+// and is zero when it is a popular search term.
+type Suggestion struct {
+	Text      string `json:"text"`
+	ProductID int    `json:"productid,omitempty"`
+	UnitSold  int    `json:"unitsold"`
+}
+
+// Complete returns up to n suggestions for a partially typed
+// search. Product names containing a word that starts with
+// the search are suggested first, followed by popular terms
+// that complete the last word of the search. Both are ranked
+// by the units sold of the products they belong to.
+func (idx *IndexSlice) Complete(text string, n int) []Suggestion {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" || n <= 0 {
+		return nil
+	}
+
+	// the last word is the one being typed, the words
+	// before it are kept in the suggested terms
+	var head, last string
+	if i := strings.LastIndexAny(text, " \t"); i >= 0 {
+		head, last = text[:i+1], text[i+1:]
+	} else {
+		last = text
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// a name is suggested once even if several of its
+	// words start with the search
+	names := []Suggestion{}
+	seen := map[int]bool{}
+	c := &idx.completions
+	for i := c.searchName(text, 0); i < len(c.names) && strings.HasPrefix(c.names[i].key, text); i++ {
+		id := c.names[i].id
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		doc := idx.docs[id]
+		names = append(names, Suggestion{Text: doc.name, ProductID: id, UnitSold: doc.unitSold})
+	}
+
+	terms := []Suggestion{}
+	for i := sort.SearchStrings(c.terms, last); i < len(c.terms) && strings.HasPrefix(c.terms[i], last); i++ {
+		word := c.terms[i]
+		terms = append(terms, Suggestion{Text: head + word, UnitSold: idx.words[word].unitSold})
+	}
+
+	sortSuggestions(names)
+	sortSuggestions(terms)
+
+	// give product names half of the suggestions, or
+	// more if there are not enough terms to fill them
+	k := (n + 1) / 2
+	if len(terms) < n-k {
+		k = n - len(terms)
+	}
+	if k > len(names) {
+		k = len(names)
+	}
+	suggestions := names[:k]
+	for _, term := range terms {
+		if len(suggestions) == n {
+			break
+		}
+		suggestions = append(suggestions, term)
+	}
+	return suggestions
+}
+
+// completions holds the product names and words of an
+// IndexSlice in sorted order, so the completions of a
+// search are found by binary searching for its prefix
+// instead of scanning every product.
+type completions struct {
+	// names holds an entry for every word of every product
+	// name, keyed by the lowercase name from that word on,
+	// so a search matches a name at the start of any word.
+	names []nameKey
+
+	// terms holds the keys of the words map.
+	terms []string
+}
+
+// nameKey is an entry of the sorted product names.
+type nameKey struct {
+	key string
+	id  int
+}
+
+// nameKeys returns the entries of the product name.
+func nameKeys(name string, id int) []nameKey {
+	s := strings.ToLower(name)
+	keys := []nameKey{}
+	for i := 0; i < len(s); i++ {
+		if (i == 0 || s[i-1] == ' ') && s[i] != ' ' {
+			keys = append(keys, nameKey{s[i:], id})
+		}
+	}
+	return keys
+}
+
+// searchName returns the position in names of the first
+// entry which is not before key and id.
+func (c *completions) searchName(key string, id int) int {
+	return sort.Search(len(c.names), func(i int) bool {
+		n := c.names[i]
+		return n.key > key || n.key == key && n.id >= id
+	})
+}
+
+// addName inserts the entries of a product name.
+func (c *completions) addName(name string, id int) {
+	for _, k := range nameKeys(name, id) {
+		i := c.searchName(k.key, k.id)
+		c.names = append(c.names, nameKey{})
+		copy(c.names[i+1:], c.names[i:])
+		c.names[i] = k
+	}
+}
+
+// removeName deletes the entries of a product name.
+func (c *completions) removeName(name string, id int) {
+	for _, k := range nameKeys(name, id) {
+		i := c.searchName(k.key, k.id)
+		if i < len(c.names) && c.names[i] == k {
+			c.names = append(c.names[:i], c.names[i+1:]...)
+		}
+	}
+}
+
+// addTerm inserts a word which is new to the words map.
+func (c *completions) addTerm(word string) {
+	i := sort.SearchStrings(c.terms, word)
+	c.terms = append(c.terms, "")
+	copy(c.terms[i+1:], c.terms[i:])
+	c.terms[i] = word
+}
+
+// removeTerm deletes a word which is removed from the
+// words map.
+func (c *completions) removeTerm(word string) {
+	i := sort.SearchStrings(c.terms, word)
+	if i < len(c.terms) && c.terms[i] == word {
+		c.terms = append(c.terms[:i], c.terms[i+1:]...)
+	}
+}
+
+// sortSuggestions orders suggestions from the most to the
+// least units sold, breaking ties alphabetically.
+func sortSuggestions(s []Suggestion) {
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].UnitSold == s[j].UnitSold {
+			return s[i].Text < s[j].Text
+		}
+		return s[i].UnitSold > s[j].UnitSold
+	})
+}
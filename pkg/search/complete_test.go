@@ -0,0 +1,87 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"testing"
+)
+
+func Test_IndexSlice_Complete(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Chicken Thigh", Keyword: "chicken meat", UnitSold: 50},
+		{ProductID: 2, Name: "Chicken Breast", Keyword: "chicken meat", UnitSold: 120},
+		{ProductID: 3, Name: "Chilli Padi", Keyword: "chilli spice", UnitSold: 10},
+		{ProductID: 4, Name: "Roast Chicken", Keyword: "chicken", UnitSold: 5},
+	})
+
+	tests := []struct {
+		name string
+		text string
+		n    int
+		want []string
+	}{
+		{
+			name: "Names ranked by units sold, then terms",
+			text: "chi",
+			n:    5,
+			want: []string{"Chicken Breast", "Chicken Thigh", "Chilli Padi", "chicken", "chilli"},
+		},
+		{
+			name: "Terms fill suggestions when names run out",
+			text: "sp",
+			n:    4,
+			want: []string{"spice"},
+		},
+		{
+			name: "Earlier words are kept in suggested terms",
+			text: "Roast chi",
+			n:    3,
+			want: []string{"Roast Chicken", "roast chicken", "roast chilli"},
+		},
+		{
+			name: "Empty text",
+			text: " ",
+			n:    5,
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := idx.Complete(tt.text, tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Complete(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			for i := range got {
+				if got[i].Text != tt.want[i] {
+					t.Errorf("Complete(%q)[%v] = %q, want %q", tt.text, i, got[i].Text, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_Complete_Update(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Chicken Thigh", Keyword: "chicken", UnitSold: 50},
+		{ProductID: 2, Name: "Chilli Padi", Keyword: "chilli", UnitSold: 10},
+	})
+	idx.UpdateProduct(&models.Product{ProductID: 1, Name: "Duck Thigh", Keyword: "duck", UnitSold: 50})
+	idx.RemoveProduct(2)
+	idx.AddProduct(&models.Product{ProductID: 3, Name: "Thigh Thigh", Keyword: "thigh", UnitSold: 5})
+
+	got := idx.Complete("thi", 5)
+	want := []string{"Duck Thigh", "Thigh Thigh", "thigh"}
+	if len(got) != len(want) {
+		t.Fatalf("Complete() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i].Text != want[i] {
+			t.Errorf("Complete()[%v] = %q, want %q", i, got[i].Text, want[i])
+		}
+	}
+	if got := idx.Complete("chi", 5); len(got) != 0 {
+		t.Errorf("Complete() of removed words = %v, want none", got)
+	}
+}
@@ -0,0 +1,197 @@
+package search
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"ProjectGoLive/pkg/models"
+	"ProjectGoLive/pkg/promo"
+)
+
+// PriceRange is a bucket of prices for filtering search
+// results. A Max of zero leaves the range unbounded.
+type PriceRange struct {
+	Label string
+	Min   float64
+	Max   float64
+}
+
+// PriceRanges are the price buckets buyers can filter on.
+// Prices are compared after the discount is applied.
+var PriceRanges = []PriceRange{
+	{"Under $5", 0, 5},
+	{"$5 - $10", 5, 10},
+	{"$10 - $20", 10, 20},
+	{"$20 - $50", 20, 50},
+	{"$50 and above", 50, 0},
+}
+
+// contains reports whether price falls in the range.
+func (pr PriceRange) contains(price float64) bool {
+	return price >= pr.Min && (pr.Max == 0 || price < pr.Max)
+}
+
+// Filter holds the facet values selected by the user. A
+// negative CategoryID, PriceRange or DiscountID, or an empty
+// SellerID, does not filter on that facet.
+type Filter struct {
+	CategoryID int
+	PriceRange int
+	DiscountID int
+	SellerID   string
+	InStock    bool
+}
+
+// NoFilter is a Filter which keeps every product.
+var NoFilter = Filter{CategoryID: -1, PriceRange: -1, DiscountID: -1}
+
+// facets which a product is matched against
+const (
+	facetCategory = iota
+	facetPrice
+	facetDiscount
+	facetSeller
+	facetStock
+	numFacets
+)
+
+// FacetValue is a value the search results can be filtered
+// on, with the number of products that would be left after
+// filtering on it.
+type FacetValue struct {
+	Label    string
+	Value    string
+	Count    int
+	Selected bool
+}
+
+// Facets holds the values of every facet of the search
+// results, excluding values which no product has.
+type Facets struct {
+	Categories []FacetValue
+	Prices     []FacetValue
+	Discounts  []FacetValue
+	Sellers    []FacetValue
+	InStock    FacetValue
+}
+
+// Apply returns the products which pass every facet of
+// the Filter, in their original order.
+func (f Filter) Apply(products []*models.Product) []*models.Product {
+	now := time.Now()
+	filtered := []*models.Product{}
+	for _, p := range products {
+		if f.matches(p, -1, now) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// Facets counts the products for every facet value. The
+// count of a value is taken with every other facet of the
+// Filter applied, so it is the number of products the
+// user would see after selecting that value.
+func (f Filter) Facets(products []*models.Product) *Facets {
+	categories := make([]int, len(models.Category))
+	discounts := make([]int, len(models.Discount))
+	prices := make([]int, len(PriceRanges))
+	sellers := map[string]int{}
+	inStock := 0
+
+	now := time.Now()
+	for _, p := range products {
+		if f.matches(p, facetCategory, now) && p.CategoryID >= 0 && p.CategoryID < len(categories) {
+			categories[p.CategoryID]++
+		}
+		if f.matches(p, facetPrice, now) {
+			for i, pr := range PriceRanges {
+				if pr.contains(finalPrice(p, now)) {
+					prices[i]++
+				}
+			}
+		}
+		if f.matches(p, facetDiscount, now) && p.DiscountID >= 0 && p.DiscountID < len(discounts) {
+			discounts[p.DiscountID]++
+		}
+		if f.matches(p, facetSeller, now) {
+			sellers[p.SellerID]++
+		}
+		if f.matches(p, facetStock, now) && p.Inventory > 0 {
+			inStock++
+		}
+	}
+
+	facets := &Facets{
+		InStock: FacetValue{Label: "In stock", Value: "1", Count: inStock, Selected: f.InStock},
+	}
+	for i, name := range models.Category {
+		if categories[i] > 0 || f.CategoryID == i {
+			facets.Categories = append(facets.Categories, FacetValue{name, name, categories[i], f.CategoryID == i})
+		}
+	}
+	for i, pr := range PriceRanges {
+		if prices[i] > 0 || f.PriceRange == i {
+			facets.Prices = append(facets.Prices, FacetValue{pr.Label, strconv.Itoa(i), prices[i], f.PriceRange == i})
+		}
+	}
+	for i, name := range models.Discount {
+		if discounts[i] > 0 || f.DiscountID == i {
+			facets.Discounts = append(facets.Discounts, FacetValue{name, name, discounts[i], f.DiscountID == i})
+		}
+	}
+	for id, count := range sellers {
+		facets.Sellers = append(facets.Sellers, FacetValue{id, id, count, f.SellerID == id})
+	}
+	if _, ok := sellers[f.SellerID]; !ok && f.SellerID != "" {
+		facets.Sellers = append(facets.Sellers, FacetValue{f.SellerID, f.SellerID, 0, true})
+	}
+	sort.Slice(facets.Sellers, func(i, j int) bool {
+		if facets.Sellers[i].Count == facets.Sellers[j].Count {
+			return facets.Sellers[i].Label < facets.Sellers[j].Label
+		}
+		return facets.Sellers[i].Count > facets.Sellers[j].Count
+	})
+
+	return facets
+}
+
+// matches reports whether the product passes every facet
+// of the Filter except skip, at its price at now.
+func (f Filter) matches(p *models.Product, skip int, now time.Time) bool {
+	for facet := 0; facet < numFacets; facet++ {
+		if facet == skip {
+			continue
+		}
+		switch facet {
+		case facetCategory:
+			if f.CategoryID >= 0 && p.CategoryID != f.CategoryID {
+				return false
+			}
+		case facetPrice:
+			if f.PriceRange >= 0 && f.PriceRange < len(PriceRanges) && !PriceRanges[f.PriceRange].contains(finalPrice(p, now)) {
+				return false
+			}
+		case facetDiscount:
+			if f.DiscountID >= 0 && p.DiscountID != f.DiscountID {
+				return false
+			}
+		case facetSeller:
+			if f.SellerID != "" && p.SellerID != f.SellerID {
+				return false
+			}
+		case facetStock:
+			if f.InStock && p.Inventory <= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// finalPrice returns the price the product sells at, at
+// now, after its discount or promotion.
+func finalPrice(p *models.Product, now time.Time) float64 {
+	return promo.Price(p.Price, p.DiscountID, p.Promotion, now)
+}
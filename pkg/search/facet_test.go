@@ -0,0 +1,87 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"testing"
+	"time"
+)
+
+var facetProducts = []*models.Product{
+	{ProductID: 1, CategoryID: 2, Price: 12, DiscountID: 0, SellerID: "ahseng", Inventory: 5},
+	{ProductID: 2, CategoryID: 2, Price: 30, DiscountID: 4, SellerID: "ahseng", Inventory: 0},
+	{ProductID: 3, CategoryID: 0, Price: 4, DiscountID: 0, SellerID: "meiling", Inventory: 2},
+	{ProductID: 4, CategoryID: 1, Price: 8, DiscountID: 2, SellerID: "meiling", Inventory: 1},
+}
+
+func Test_Filter_Apply(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int
+	}{
+		{name: "No filter", filter: NoFilter, want: []int{1, 2, 3, 4}},
+		{name: "Category", filter: Filter{CategoryID: 2, PriceRange: -1, DiscountID: -1}, want: []int{1, 2}},
+		{name: "Discounted price", filter: Filter{CategoryID: -1, PriceRange: 3, DiscountID: -1}, want: []int{2}},
+		{name: "Seller and stock", filter: Filter{CategoryID: -1, PriceRange: -1, DiscountID: -1, SellerID: "ahseng", InStock: true}, want: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []int{}
+			for _, p := range tt.filter.Apply(facetProducts) {
+				got = append(got, p.ProductID)
+			}
+			if !sameIDs(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Filter_Apply_Promotion(t *testing.T) {
+	// a product on sale is filtered by its sale price
+	now := time.Now()
+	products := []*models.Product{
+		{ProductID: 5, Price: 8, Promotion: &models.Promotion{Kind: models.PromotionAmount, Value: 4, Starts: now.Add(-time.Hour), Ends: now.Add(time.Hour)}},
+		{ProductID: 6, Price: 8, Promotion: &models.Promotion{Kind: models.PromotionAmount, Value: 4, Starts: now.Add(-2 * time.Hour), Ends: now.Add(-time.Hour)}},
+	}
+	f := Filter{CategoryID: -1, PriceRange: 0, DiscountID: -1}
+
+	got := []int{}
+	for _, p := range f.Apply(products) {
+		got = append(got, p.ProductID)
+	}
+	if !sameIDs(got, []int{5}) {
+		t.Errorf("Apply() = %v, want %v", got, []int{5})
+	}
+}
+
+func Test_Filter_Facets(t *testing.T) {
+	f := NoFilter
+	f.CategoryID = 2
+	facets := f.Facets(facetProducts)
+
+	// the selected facet still counts every category, so the
+	// user can see what switching category would show
+	counts := map[string]int{}
+	for _, v := range facets.Categories {
+		counts[v.Value] = v.Count
+	}
+	want := map[string]int{"Frozen Food": 1, "Staples": 1, "Meat and Seafood": 2}
+	for k, v := range want {
+		if counts[k] != v {
+			t.Errorf("category %q counted %d, want %d", k, counts[k], v)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Errorf("categories = %v, want %v", counts, want)
+	}
+
+	// other facets only count products in the selected category
+	if len(facets.Sellers) != 1 || facets.Sellers[0].Value != "ahseng" || facets.Sellers[0].Count != 2 {
+		t.Errorf("sellers = %v, want ahseng (2)", facets.Sellers)
+	}
+	if facets.InStock.Count != 1 {
+		t.Errorf("in stock counted %d, want 1", facets.InStock.Count)
+	}
+}
@@ -0,0 +1,99 @@
+package search
+
+import "unicode/utf8"
+
+// fuzzyPenalty scales down the score of a term that was
+// matched through a misspelling, so products that match
+// the search terms exactly are ranked above them.
+const fuzzyPenalty = 0.5
+
+// maxEdits returns the number of typos tolerated for a
+// search term. Short terms have to match exactly, since
+// a single edit turns them into a different word.
+func maxEdits(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// fuzzyTerms looks through the vocabulary of the IndexSlice
+// for terms within maxEdits of the search term and returns
+// them mapped to their edit distance. The caller must hold
+// the read lock.
+func (idx *IndexSlice) fuzzyTerms(term string) map[string]int {
+	max := maxEdits(term)
+	if max == 0 {
+		return nil
+	}
+
+	matches := map[string]int{}
+	for _, m := range idx.maps {
+		for candidate := range m {
+			if _, ok := matches[candidate]; ok {
+				continue
+			}
+			if d := editDistance(term, candidate, max); d <= max {
+				matches[candidate] = d
+			}
+		}
+	}
+	return matches
+}
+
+// editDistance returns the Levenshtein distance between a
+// and b. editDistance stops early and returns max+1 once
+// the distance is known to be greater than max.
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
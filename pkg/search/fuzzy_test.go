@@ -0,0 +1,62 @@
+package search
+
+import "testing"
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		max  int
+		want int
+	}{
+		{name: "Identical", a: "chicken", b: "chicken", max: 2, want: 0},
+		{name: "Deletion", a: "chiken", b: "chicken", max: 2, want: 1},
+		{name: "Substitution", a: "toest", b: "toast", max: 2, want: 1},
+		{name: "Transposition", a: "toats", b: "toast", max: 2, want: 2},
+		{name: "Beyond max", a: "durian", b: "chicken", max: 2, want: 3},
+		{name: "Length difference beyond max", a: "rice", b: "ricecakes", max: 2, want: 3},
+		{name: "Non-ASCII", a: "菜心", b: "菜", max: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := editDistance(tt.a, tt.b, tt.max); got != tt.want {
+				t.Errorf("editDistance(%q, %q, %v) = %v, want %v", tt.a, tt.b, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_SearchFuzzy(t *testing.T) {
+	idx := newTestIndex()
+
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{name: "Missing letter", text: "chiken", want: []int{3}},
+		{name: "Misspelt second word", text: "kaya toats", want: []int{2}},
+		{name: "Short terms must be exact", text: "fsh", want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := idx.Search(tt.text)
+			if !sameIDs(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_SearchExactFirst(t *testing.T) {
+	idx := newTestIndex()
+
+	// "rice" matches product 1 exactly while "toats" only
+	// matches product 2 through a typo
+	_, scores := idx.Search("rice toats")
+	if scores[1] <= scores[2] {
+		t.Errorf("exact match scored %v, fuzzy match scored %v", scores[1], scores[2])
+	}
+}
@@ -1,109 +1,355 @@
 package search
 
 import (
-	"strings"
 	"sync"
-	"unicode"
+	"time"
 
 	"ProjectGoLive/pkg/models"
 
-	sw "github.com/bbalet/stopwords"
 	_ "github.com/go-sql-driver/mysql"
-	snowballeng "github.com/kljensen/snowball/english"
+)
+
+// fields indexed for every product, in the order
+// their maps are held in the IndexSlice.
+const (
+	fieldName = iota
+	fieldDesc
+	fieldKeyword
+	numFields
 )
 
 // IndexSlice conains maps mapping words to a slice of integers
 // containing ProductID(s) which has the word in their name,
-// description or keywords fields
-type IndexSlice []map[string][]int
+// description or keywords fields. An IndexSlice is safe for
+// concurrent use: Search holds a read lock for the whole
+// query so it always sees a single consistent index.
+type IndexSlice struct {
+	mu   sync.RWMutex
+	maps []map[string][]posting
+
+	// bm25 holds the parameters used to score matches.
+	bm25 BM25
+
+	// languages holds the analyzers for each language
+	// products can be written in.
+	languages []Language
+
+	// synonyms is the synonym dictionary searches are
+	// expanded with. synonymTerms maps every analysis of a
+	// synonym to the analyses of the other synonyms in its
+	// group, and synonymWords is the most words a synonym has.
+	synonyms     *Synonyms
+	synonymTerms map[string][][]string
+	synonymWords int
+
+	// length holds the total number of tokens indexed
+	// for each field, for computing the average field
+	// length of the products.
+	length [numFields]int
+
+	// docs maps a ProductID to what was indexed for it so
+	// the product can be removed without walking every
+	// posting list.
+	docs map[int]*document
+
+	// words maps the unstemmed words found in product names
+	// and keywords to how popular they are, for completing
+	// partially typed search terms.
+	words map[string]*wordStat
+
+	// completions holds the names of the docs and the
+	// words in sorted order.
+	completions completions
+
+	// synced is the latest modification time of the products
+	// loaded from the database, so CatchUp knows which products
+	// have changed since.
+	synced time.Time
+
+	// rebuildMu serializes calls to Rebuild. While a rebuild
+	// is in progress, journal records every write so it can
+	// be replayed onto the rebuilt index before it is swapped in.
+	rebuildMu sync.Mutex
+	journal   []change
+}
+
+// posting records that a product has a term in one of
+// its fields, and the positions of the term in the field.
+type posting struct {
+	id  int
+	pos []int
+}
+
+// document holds what was indexed for a single product.
+type document struct {
+	name     string
+	unitSold int
+	tokens   [][]string
+	length   [numFields]int
+	words    []string
+}
+
+// wordStat counts the products that have a word in their
+// name or keywords, and the units sold of those products.
+type wordStat struct {
+	products int
+	unitSold int
+}
+
+// change is a write made to the IndexSlice while it
+// is being rebuilt. A nil product records a removal.
+type change struct {
+	id      int
+	product *models.Product
+}
+
+// NewIndexSlice returns an empty IndexSlice which analyzes
+// products in DefaultLanguages and scores matches with
+// DefaultBM25.
+func NewIndexSlice() *IndexSlice {
+	idx := &IndexSlice{
+		bm25:      DefaultBM25,
+		languages: DefaultLanguages,
+		maps:      make([]map[string][]posting, numFields),
+		docs:      map[int]*document{},
+		words:     map[string]*wordStat{},
+	}
+	for i := range idx.maps {
+		idx.maps[i] = map[string][]posting{}
+	}
+	return idx
+}
 
 // Add populates the IndexSlice by ranging through a list of
 // Product and adding words from their name, decription, and
 // keywords into their respective maps in the IndexSlice
-func (idx IndexSlice) Add(products []*models.Product) {
-	var wg sync.WaitGroup
+func (idx *IndexSlice) Add(products []*models.Product) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
 
 	for _, product := range products {
-		wg.Add(3)
-		go func() {
-			defer wg.Done()
-			for _, token := range analyze(product.Name) {
-				ids := idx[0][token]
-				if ids != nil && ids[len(ids)-1] == product.ProductID {
-					// Don't add same ID twice.
-					continue
-				}
-				idx[0][token] = append(ids, product.ProductID)
-			}
-		}()
-		go func() {
-			defer wg.Done()
-			for _, token := range analyze(product.Desc) {
-				ids := idx[1][token]
-				if ids != nil && ids[len(ids)-1] == product.ProductID {
-					// Don't add same ID twice.
-					continue
-				}
-				idx[1][token] = append(ids, product.ProductID)
-			}
-		}()
-		go func() {
-			defer wg.Done()
-			for _, token := range analyze(product.Keyword) {
-				ids := idx[2][token]
-				if ids != nil && ids[len(ids)-1] == product.ProductID {
-					// Don't add same ID twice.
-					continue
-				}
-				idx[2][token] = append(ids, product.ProductID)
-			}
-		}()
-		wg.Wait()
+		idx.add(product)
 	}
 }
 
-// analyze prepares the text for search.
-func analyze(text string) []string {
-	text = stopword(text)            //Step 1
-	tokens := tokenize(text)         //Step 2
-	tokens = lowercaseFilter(tokens) //Step 3
-	tokens = stemmerFilter(tokens)   //Step 4
-	return tokens
+// AddProduct adds the postings of a single Product to the
+// IndexSlice. AddProduct should be called after a Product
+// is inserted into the database.
+func (idx *IndexSlice) AddProduct(product *models.Product) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.record(change{product.ProductID, product})
+	idx.add(product)
 }
 
-// stopword removes stopwords to reduce false positives
-// in the search result.
-func stopword(text string) string {
-	return sw.CleanString(text, "en", true)
+// UpdateProduct replaces the postings of a Product in the
+// IndexSlice with postings built from its current name,
+// description and keywords.
+func (idx *IndexSlice) UpdateProduct(product *models.Product) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
 
+	idx.record(change{product.ProductID, product})
+	idx.remove(product.ProductID)
+	idx.add(product)
 }
 
-// tokenize breaks down a phases and sentences
-// into individual characters.
-func tokenize(text string) []string {
-	return strings.FieldsFunc(text, func(r rune) bool {
-		// Split on any character that is not a letter or a number.
-		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
-	})
+// RemoveProduct removes every posting of the Product with
+// the specified ProductID from the IndexSlice.
+func (idx *IndexSlice) RemoveProduct(id int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.record(change{id, nil})
+	idx.remove(id)
 }
 
-// lowerCaseFilter converts every token to lower
-// case so the search is not case-sensitive.
-func lowercaseFilter(tokens []string) []string {
-	r := make([]string, len(tokens))
-	for i, token := range tokens {
-		r[i] = strings.ToLower(token)
+// Rebuild replaces the contents of the IndexSlice with an index
+// built from the products returned by load. The new index is built
+// without blocking Search and is swapped in atomically, after any
+// product changes made during the rebuild have been replayed onto it.
+// The IndexSlice is left untouched if load returns an error.
+func (idx *IndexSlice) Rebuild(load func() ([]*models.Product, error)) error {
+	idx.rebuildMu.Lock()
+	defer idx.rebuildMu.Unlock()
+
+	// start journaling before the products are loaded so
+	// no change made after the load is lost in the swap
+	idx.mu.Lock()
+	idx.journal = []change{}
+	languages := idx.languages
+	idx.mu.Unlock()
+
+	products, err := load()
+	if err != nil {
+		idx.mu.Lock()
+		idx.journal = nil
+		idx.mu.Unlock()
+		return err
+	}
+
+	fresh := NewIndexSlice()
+	fresh.languages = languages
+	for _, product := range products {
+		fresh.add(product)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.replay(fresh)
+	idx.maps, idx.docs, idx.words = fresh.maps, fresh.docs, fresh.words
+	idx.length, idx.synced = fresh.length, fresh.synced
+	idx.completions = fresh.completions
+	idx.journal = nil
+
+	return nil
+}
+
+// record adds a change to the journal if a rebuild is in
+// progress. The caller must hold the write lock.
+func (idx *IndexSlice) record(c change) {
+	if idx.journal != nil {
+		idx.journal = append(idx.journal, c)
+	}
+}
+
+// replay applies the journal to target. The caller must
+// hold the write lock.
+func (idx *IndexSlice) replay(target *IndexSlice) {
+	for _, c := range idx.journal {
+		if c.product == nil {
+			target.remove(c.id)
+			continue
+		}
+		target.add(c.product)
 	}
-	return r
 }
 
-// stemmerFilter stems every token so relevant results
-// that do not match the search term exactly are include
-// in the results.
-func stemmerFilter(tokens []string) []string {
-	r := make([]string, len(tokens))
-	for i, token := range tokens {
-		r[i] = snowballeng.Stem(token, false)
+// add indexes a product. The caller must hold the write lock.
+func (idx *IndexSlice) add(product *models.Product) {
+	if _, ok := idx.docs[product.ProductID]; ok {
+		idx.remove(product.ProductID)
+	}
+
+	fields := idx.language(product).Fields
+
+	var wg sync.WaitGroup
+	tokens := make([][]string, numFields)
+	for i, text := range []string{product.Name, product.Desc, product.Keyword} {
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			tokens[i] = termsOf(fields.analyzer(i).Analyze(text))
+		}(i, text)
+	}
+	wg.Wait()
+
+	doc := &document{
+		name:     product.Name,
+		unitSold: product.UnitSold,
+		tokens:   make([][]string, numFields),
+		words:    unique(words(product.Name + " " + product.Keyword)),
+	}
+	for i, fieldTokens := range tokens {
+		pos := map[string][]int{}
+		for j, token := range fieldTokens {
+			pos[token] = append(pos[token], j)
+		}
+		doc.length[i] = len(fieldTokens)
+		doc.tokens[i] = unique(fieldTokens)
+		for _, token := range doc.tokens[i] {
+			idx.maps[i][token] = append(idx.maps[i][token], posting{product.ProductID, pos[token]})
+		}
+		idx.length[i] += doc.length[i]
+	}
+	for _, word := range doc.words {
+		stat, ok := idx.words[word]
+		if !ok {
+			stat = &wordStat{}
+			idx.words[word] = stat
+			idx.completions.addTerm(word)
+		}
+		stat.products++
+		stat.unitSold += doc.unitSold
+	}
+	idx.docs[product.ProductID] = doc
+	idx.completions.addName(doc.name, product.ProductID)
+	if product.Modified.After(idx.synced) {
+		idx.synced = product.Modified
+	}
+}
+
+// language returns the language the product is written
+// in, or the first language of the index if the product
+// is written in a language it does not analyze.
+func (idx *IndexSlice) language(product *models.Product) Language {
+	code := DetectLanguage(product.Name + " " + product.Desc + " " + product.Keyword)
+	for _, lang := range idx.languages {
+		if lang.Code == code {
+			return lang
+		}
+	}
+	return idx.languages[0]
+}
+
+// remove deletes a product's postings. The caller must
+// hold the write lock.
+func (idx *IndexSlice) remove(id int) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+
+	for i, fieldTokens := range doc.tokens {
+		for _, token := range fieldTokens {
+			postings := idx.maps[i][token]
+			for j, p := range postings {
+				if p.id == id {
+					postings = append(postings[:j:j], postings[j+1:]...)
+					break
+				}
+			}
+			if len(postings) == 0 {
+				delete(idx.maps[i], token)
+				continue
+			}
+			idx.maps[i][token] = postings
+		}
+		idx.length[i] -= doc.length[i]
+	}
+	for _, word := range doc.words {
+		stat := idx.words[word]
+		stat.products--
+		stat.unitSold -= doc.unitSold
+		if stat.products == 0 {
+			delete(idx.words, word)
+			idx.completions.removeTerm(word)
+		}
+	}
+	delete(idx.docs, id)
+	idx.completions.removeName(doc.name, id)
+}
+
+// unique removes repeated tokens so the same ProductID
+// is not added to a posting list twice.
+func unique(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	r := tokens[:0]
+	for _, token := range tokens {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		r = append(r, token)
 	}
 	return r
 }
+
+// words prepares the text for completing search terms.
+// Unlike the analyzers, words does not stem the tokens so
+// they can be shown to the user as they are.
+func words(text string) []string {
+	return termsOf(StopwordFilter("en")(LowercaseFilter(WordTokenizer(text))))
+}
@@ -0,0 +1,172 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// test data
+var products = []*models.Product{
+	{ProductID: 1, Name: "Jasmine Rice", Desc: "Fragrant long grain rice from Thailand.", Keyword: "rice staples"},
+	{ProductID: 2, Name: "Kaya Toast", Desc: "Toast with coconut jam.", Keyword: "breakfast"},
+	{ProductID: 3, Name: "Frozen Chicken Thigh", Desc: "Boneless chicken thigh, frozen.", Keyword: "chicken meat"},
+	{ProductID: 4, Name: "Fish Ball", Desc: "Bouncy fish balls for noodle soup.", Keyword: "fish seafood"},
+}
+
+func newTestIndex() *IndexSlice {
+	idx := NewIndexSlice()
+	idx.Add(products)
+	return idx
+}
+
+func Test_IndexSlice_Search(t *testing.T) {
+	idx := newTestIndex()
+
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{name: "Match in name", text: "jasmine", want: []int{1}},
+		{name: "Match in description", text: "coconut", want: []int{2}},
+		{name: "Match in keyword", text: "seafood", want: []int{4}},
+		{name: "Stemmed match", text: "chickens", want: []int{3}},
+		{name: "No match", text: "durian", want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := idx.Search(tt.text)
+			if !sameIDs(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_UpdateProduct(t *testing.T) {
+	idx := newTestIndex()
+
+	idx.AddProduct(&models.Product{ProductID: 5, Name: "Durian", Desc: "Mao Shan Wang.", Keyword: "fruit"})
+	if got, _ := idx.Search("durian"); !sameIDs(got, []int{5}) {
+		t.Errorf("after AddProduct got %v, want %v", got, []int{5})
+	}
+
+	idx.UpdateProduct(&models.Product{ProductID: 5, Name: "Mangosteen", Desc: "Queen of fruits.", Keyword: "fruit"})
+	if got, _ := idx.Search("durian"); len(got) != 0 {
+		t.Errorf("after UpdateProduct got %v for old name, want none", got)
+	}
+	if got, _ := idx.Search("mangosteen"); !sameIDs(got, []int{5}) {
+		t.Errorf("after UpdateProduct got %v, want %v", got, []int{5})
+	}
+
+	idx.RemoveProduct(5)
+	if got, _ := idx.Search("fruit"); len(got) != 0 {
+		t.Errorf("after RemoveProduct got %v, want none", got)
+	}
+}
+
+func Test_IndexSlice_Rebuild(t *testing.T) {
+	idx := newTestIndex()
+
+	// a product added while the rebuild is loading from
+	// the database must survive the swap
+	err := idx.Rebuild(func() ([]*models.Product, error) {
+		idx.AddProduct(&models.Product{ProductID: 5, Name: "Durian", Keyword: "fruit"})
+		return products[:2], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := idx.Search("durian"); !sameIDs(got, []int{5}) {
+		t.Errorf("journaled product: got %v, want %v", got, []int{5})
+	}
+	if got, _ := idx.Search("chicken"); len(got) != 0 {
+		t.Errorf("product missing from rebuild: got %v, want none", got)
+	}
+
+	// a failed load leaves the index untouched
+	err = idx.Rebuild(func() ([]*models.Product, error) {
+		return nil, errors.New("database unreachable")
+	})
+	if err == nil {
+		t.Error("Rebuild returned nil error for a failed load")
+	}
+	if got, _ := idx.Search("jasmine"); !sameIDs(got, []int{1}) {
+		t.Errorf("after failed rebuild got %v, want %v", got, []int{1})
+	}
+}
+
+// Test_IndexSlice_Concurrent exercises Search alongside product
+// updates and rebuilds. Run with -race to detect data races.
+func Test_IndexSlice_Concurrent(t *testing.T) {
+	idx := newTestIndex()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ids, scores := idx.Search("chicken rice fish")
+				if len(ids) != len(scores) {
+					t.Errorf("Search returned %v ids but %v scores", len(ids), len(scores))
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			p := &models.Product{ProductID: 100 + j%5, Name: fmt.Sprintf("Chicken %v", j), Keyword: "chicken"}
+			idx.UpdateProduct(p)
+			if j%3 == 0 {
+				idx.RemoveProduct(p.ProductID)
+			}
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 10; j++ {
+			err := idx.Rebuild(func() ([]*models.Product, error) { return products, nil })
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	// the original products are always present after the rebuilds
+	if got, _ := idx.Search("jasmine"); !sameIDs(got, []int{1}) {
+		t.Errorf("after concurrent use got %v, want %v", got, []int{1})
+	}
+}
+
+// sameIDs reports whether a and b hold the same ProductIDs
+// regardless of order.
+func sameIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := map[int]int{}
+	for _, v := range a {
+		count[v]++
+	}
+	for _, v := range b {
+		count[v]--
+		if count[v] < 0 {
+			return false
+		}
+	}
+	return true
+}
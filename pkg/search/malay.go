@@ -0,0 +1,74 @@
+package search
+
+import "strings"
+
+// MalayStemmer removes the common Malay affixes from every
+// token, so "sayuran" matches "sayur" and "dimasak" matches
+// "masak". It is a light stemmer without a dictionary: it
+// only removes an affix if enough of the word is left, and
+// does not restore the first letter of a root dropped by
+// the meN- and peN- prefixes, except for s.
+func MalayStemmer(tokens []Token) []Token {
+	r := make([]Token, len(tokens))
+	for i, token := range tokens {
+		r[i] = Token{stemMalay(token.Term), token.Start, token.End}
+	}
+	return r
+}
+
+// affixes removed by stemMalay, in the order they are tried
+var (
+	malayParticles   = []string{"lah", "kah", "tah", "pun"}
+	malayPossessives = []string{"nya", "ku", "mu"}
+	malaySuffixes    = []string{"kan", "an"}
+	malayPrefixes    = []string{"meng", "peng", "meny", "peny", "ber", "ter", "di"}
+)
+
+// stemMalay removes a particle, a possessive pronoun,
+// a suffix and a prefix from the word, in that order.
+func stemMalay(word string) string {
+	word = trimMalaySuffix(word, malayParticles, 4)
+	word = trimMalaySuffix(word, malayPossessives, 4)
+	// suffixes need a longer root so that words such
+	// as "durian" and "ikan" are left alone
+	word = trimMalaySuffix(word, malaySuffixes, 5)
+
+	for _, prefix := range malayPrefixes {
+		if !strings.HasPrefix(word, prefix) || len(word)-len(prefix) < 4 {
+			continue
+		}
+		root := word[len(prefix):]
+		if prefix == "meny" || prefix == "peny" {
+			root = "s" + root
+		}
+		return root
+	}
+	return word
+}
+
+// trimMalaySuffix removes the first of the suffixes the
+// word ends with, if at least min bytes are left.
+func trimMalaySuffix(word string, suffixes []string, min int) string {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= min {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
+
+// malayWords are common Malay words in grocery product
+// names, used by DetectLanguage.
+var malayWords = map[string]bool{
+	"air": true, "ayam": true, "bawang": true, "bayam": true,
+	"beras": true, "bihun": true, "bilis": true, "buah": true,
+	"cili": true, "daging": true, "goreng": true, "gula": true,
+	"halia": true, "ikan": true, "kacang": true, "kambing": true,
+	"kangkung": true, "kelapa": true, "kering": true, "kicap": true,
+	"kuih": true, "kunyit": true, "lada": true, "lembu": true,
+	"limau": true, "manis": true, "masak": true, "minyak": true,
+	"nasi": true, "pedas": true, "pisang": true, "rempah": true,
+	"roti": true, "sambal": true, "santan": true, "sayur": true,
+	"sayuran": true, "segar": true, "serai": true, "sotong": true,
+	"susu": true, "telur": true, "udang": true,
+}
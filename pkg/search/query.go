@@ -0,0 +1,424 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// occur describes how a group of clauses in a query
+// decides which products are in the search results.
+type occur int
+
+const (
+	// should groups add to the score of the products they
+	// match. When a query has no must groups, a product has
+	// to match at least one should group.
+	should occur = iota
+	// must groups have to be matched by every result.
+	must
+	// mustNot groups must not be matched by any result.
+	mustNot
+)
+
+// clause is a single search term or quoted phrase.
+// A phrase holds more than one analyzed term. As the
+// search may be in any language, alts holds the other
+// ways the clause is analyzed by the other languages.
+// synonyms holds the analyzed synonyms of the clause.
+type clause struct {
+	terms    []string
+	alts     [][]string
+	synonyms [][]string
+}
+
+// group is one or more clauses joined by OR. A product
+// matches a group if it matches any of the clauses.
+type group struct {
+	clauses []clause
+	occur   occur
+}
+
+// query is a parsed search query. The query syntax is:
+//
+//	fish ball      products with fish or ball
+//	"fish ball"    products with fish followed by ball
+//	+halal         products which must have halal
+//	-frozen        products which must not have frozen
+//	beef OR pork   products with beef or pork
+//
+// A + or - before the first clause of an OR group
+// applies to the whole group.
+type query struct {
+	groups []group
+}
+
+// queryWord is a word or quoted phrase of the search
+// text, before it is analyzed.
+type queryWord struct {
+	raw    string
+	occur  occur
+	quoted bool
+}
+
+// parseQuery parses the search text typed by the user into
+// a query, analyzing it in every language of the index and
+// looking up the synonyms of every clause. Clauses which
+// have no terms left after analysis in any language, such
+// as stopwords, are dropped. The caller must hold the read
+// lock.
+func (idx *IndexSlice) parseQuery(text string) query {
+	var q query
+	joinNext := false
+
+	// analyze in the language the search appears to be in
+	// first, so its terms are the ones shown to the user
+	code := DetectLanguage(text)
+	ordered := []Language{}
+	for _, lang := range idx.languages {
+		if lang.Code == code {
+			ordered = append([]Language{lang}, ordered...)
+		} else {
+			ordered = append(ordered, lang)
+		}
+	}
+
+	words := lexQuery(text)
+	for i := 0; i < len(words); {
+		w := words[i]
+		if w.raw == "OR" && w.occur == should {
+			joinNext = len(q.groups) > 0
+			i++
+			continue
+		}
+
+		// a synonym may be more than one word, such as
+		// cili padi, so read the words which make it up
+		// as a single clause
+		n := 1
+		if !w.quoted {
+			n = idx.synonymSpan(words[i:], ordered)
+		}
+		raws := make([]string, n)
+		for j := range raws {
+			raws[j] = words[i+j].raw
+		}
+		i += n
+
+		c, ok := analyzeClause(strings.Join(raws, " "), ordered)
+		if !ok {
+			joinNext = false
+			continue
+		}
+		c.synonyms = idx.synonymsOf(c)
+
+		if joinNext {
+			last := &q.groups[len(q.groups)-1]
+			last.clauses = append(last.clauses, c)
+		} else {
+			q.groups = append(q.groups, group{clauses: []clause{c}, occur: w.occur})
+		}
+		joinNext = false
+	}
+
+	return q
+}
+
+// lexQuery breaks the search text down into words and
+// quoted phrases, each with its modifier.
+func lexQuery(text string) []queryWord {
+	words := []queryWord{}
+
+	rs := []rune(text)
+	for i := 0; i < len(rs); {
+		if unicode.IsSpace(rs[i]) {
+			i++
+			continue
+		}
+
+		// read the modifier, if any
+		o := should
+		if (rs[i] == '+' || rs[i] == '-') && i+1 < len(rs) && !unicode.IsSpace(rs[i+1]) {
+			if rs[i] == '+' {
+				o = must
+			} else {
+				o = mustNot
+			}
+			i++
+		}
+
+		// read a quoted phrase up to the closing quote,
+		// or a single word up to the next space
+		var raw string
+		quoted := rs[i] == '"'
+		if quoted {
+			j := i + 1
+			for j < len(rs) && rs[j] != '"' {
+				j++
+			}
+			raw = string(rs[i+1 : j])
+			i = j + 1
+		} else {
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) {
+				j++
+			}
+			raw = string(rs[i:j])
+			i = j
+		}
+
+		words = append(words, queryWord{raw, o, quoted})
+	}
+
+	return words
+}
+
+// analyzeClause analyzes the text of a clause with the name
+// analyzer of every language. A language whose analyzer
+// leaves no terms, such as for one of its stopwords, is
+// skipped. It reports false if every analyzer leaves no
+// terms.
+func analyzeClause(raw string, languages []Language) (clause, bool) {
+	var c clause
+	for _, lang := range languages {
+		terms := termsOf(lang.Fields.Name.Analyze(raw))
+		if len(terms) == 0 {
+			continue
+		}
+		if c.terms == nil {
+			c.terms = terms
+			continue
+		}
+		if !sameTerms(terms, c.terms) && !containsTerms(c.alts, terms) {
+			c.alts = append(c.alts, terms)
+		}
+	}
+	return c, len(c.terms) > 0
+}
+
+// containsTerms reports whether list holds terms.
+func containsTerms(list [][]string, terms []string) bool {
+	for _, t := range list {
+		if sameTerms(t, terms) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameTerms reports whether a and b hold the same terms
+// in the same order.
+func sameTerms(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluate returns the products matching the query mapped
+// to their relevance score. The caller must hold the read
+// lock.
+func (idx *IndexSlice) evaluate(q query) map[int]float64 {
+	var required []map[int]float64
+	optional := map[int]float64{}
+	excluded := map[int]bool{}
+
+	for _, g := range q.groups {
+		matches := map[int]float64{}
+		for _, c := range g.clauses {
+			for id, score := range idx.matchClause(c) {
+				matches[id] += score
+			}
+		}
+
+		switch g.occur {
+		case must:
+			required = append(required, matches)
+		case mustNot:
+			for id := range matches {
+				excluded[id] = true
+			}
+		default:
+			for id, score := range matches {
+				optional[id] += score
+			}
+		}
+	}
+
+	// products have to match every must group, or
+	// any should group when there are no must groups
+	scores := map[int]float64{}
+	if len(required) > 0 {
+		for id := range required[0] {
+			scores[id] = 0
+		}
+		for _, matches := range required {
+			for id := range scores {
+				score, ok := matches[id]
+				if !ok {
+					delete(scores, id)
+					continue
+				}
+				scores[id] += score
+			}
+		}
+		for id := range scores {
+			scores[id] += optional[id]
+		}
+	} else {
+		scores = optional
+	}
+
+	for id := range excluded {
+		delete(scores, id)
+	}
+	return scores
+}
+
+// matchClause returns the products matching a clause
+// mapped to their score. A product matching more than one
+// analysis or synonym of the clause keeps its best score.
+// The caller must hold the read lock.
+func (idx *IndexSlice) matchClause(c clause) map[int]float64 {
+	scores := map[int]float64{}
+	analyses := append([][]string{c.terms}, c.alts...)
+
+	found := false
+	for _, terms := range analyses {
+		if matches, ok := idx.matchTerms(terms, 1); ok {
+			found = true
+			keepBest(scores, matches)
+		}
+	}
+	for _, terms := range c.synonyms {
+		if matches, ok := idx.matchTerms(terms, synonymWeight); ok {
+			found = true
+			keepBest(scores, matches)
+		}
+	}
+	if found {
+		return scores
+	}
+
+	// the term may be misspelt, so look for terms that
+	// are a few edits away, scoring closer terms higher
+	for _, terms := range analyses {
+		matches := map[int]float64{}
+		for candidate, dist := range idx.fuzzyTerms(terms[0]) {
+			for field := 0; field < numFields; field++ {
+				idx.score(matches, field, candidate, fuzzyPenalty/float64(dist))
+			}
+		}
+		keepBest(scores, matches)
+	}
+	return scores
+}
+
+// matchTerms returns the products matching a term or
+// phrase mapped to their score multiplied by weight. It
+// reports false if the term is not in the IndexSlice. The
+// caller must hold the read lock.
+func (idx *IndexSlice) matchTerms(terms []string, weight float64) (map[int]float64, bool) {
+	scores := map[int]float64{}
+	if len(terms) > 1 {
+		idx.matchPhrase(scores, terms, weight)
+		return scores, true
+	}
+	if !idx.contains(terms[0]) {
+		return nil, false
+	}
+	for field := 0; field < numFields; field++ {
+		idx.score(scores, field, terms[0], weight)
+	}
+	return scores, true
+}
+
+// keepBest raises the score of every product in scores
+// to its score in matches, if that is higher.
+func keepBest(scores, matches map[int]float64) {
+	for id, score := range matches {
+		if score > scores[id] {
+			scores[id] = score
+		}
+	}
+}
+
+// matchPhrase adds the score of every term in the phrase,
+// multiplied by weight, to the products which have the
+// terms next to each other, in order, in any field. The
+// caller must hold the read lock.
+func (idx *IndexSlice) matchPhrase(scores map[int]float64, terms []string, weight float64) {
+	for field := 0; field < numFields; field++ {
+		matched := idx.phraseMatches(field, terms)
+		if len(matched) == 0 {
+			continue
+		}
+		for _, term := range terms {
+			termScores := map[int]float64{}
+			idx.score(termScores, field, term, weight)
+			for id := range matched {
+				scores[id] += termScores[id]
+			}
+		}
+	}
+}
+
+// phraseMatches returns the products which have the terms
+// next to each other, in order, in the field. The caller
+// must hold the read lock.
+func (idx *IndexSlice) phraseMatches(field int, terms []string) map[int]bool {
+	// positions maps the ProductID to the positions of each
+	// term, for products which have every term in the field
+	positions := map[int][][]int{}
+	for _, p := range idx.maps[field][terms[0]] {
+		positions[p.id] = [][]int{p.pos}
+	}
+	for _, term := range terms[1:] {
+		found := map[int]bool{}
+		for _, p := range idx.maps[field][term] {
+			if pos, ok := positions[p.id]; ok {
+				positions[p.id] = append(pos, p.pos)
+				found[p.id] = true
+			}
+		}
+		for id := range positions {
+			if !found[id] {
+				delete(positions, id)
+			}
+		}
+	}
+
+	matched := map[int]bool{}
+	for id, pos := range positions {
+		for _, start := range pos[0] {
+			if followedBy(pos[1:], start) {
+				matched[id] = true
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// followedBy reports whether the i-th list of positions
+// holds start+i+1 for every list.
+func followedBy(pos [][]int, start int) bool {
+	for i, list := range pos {
+		want := start + i + 1
+		found := false
+		for _, p := range list {
+			if p == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"reflect"
+	"testing"
+)
+
+func Test_parseQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want query
+	}{
+		{
+			name: "Plain terms",
+			text: "fish ball",
+			want: query{[]group{
+				{clauses: []clause{{terms: []string{"fish"}}}, occur: should},
+				{clauses: []clause{{terms: []string{"ball"}}}, occur: should},
+			}},
+		},
+		{
+			name: "Phrase",
+			text: `"fish ball" noodles`,
+			want: query{[]group{
+				{clauses: []clause{{terms: []string{"fish", "ball"}}}, occur: should},
+				{clauses: []clause{{terms: []string{"noodl"}, alts: [][]string{{"noodles"}}}}, occur: should},
+			}},
+		},
+		{
+			name: "Required and excluded",
+			text: "+halal chicken -frozen",
+			want: query{[]group{
+				{clauses: []clause{{terms: []string{"halal"}}}, occur: must},
+				{clauses: []clause{{terms: []string{"chicken"}}}, occur: should},
+				{clauses: []clause{{terms: []string{"frozen"}}}, occur: mustNot},
+			}},
+		},
+		{
+			name: "OR group",
+			text: `+beef OR "chicken thigh"`,
+			want: query{[]group{
+				{clauses: []clause{{terms: []string{"beef"}}, {terms: []string{"chicken", "thigh"}}}, occur: must},
+			}},
+		},
+		{
+			name: "Stopwords and stray operators are dropped",
+			text: "OR per - fish OR",
+			want: query{[]group{
+				{clauses: []clause{{terms: []string{"fish"}}}, occur: should},
+			}},
+		},
+		{
+			name: "Stopword in only one language",
+			text: "kecil fish",
+			want: query{[]group{
+				{clauses: []clause{{terms: []string{"kecil"}}}, occur: should},
+				{clauses: []clause{{terms: []string{"fish"}}}, occur: should},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewIndexSlice().parseQuery(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQuery(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_SearchQuery(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Fish Ball", Desc: "Bouncy fish balls.", Keyword: "frozen"},
+		{ProductID: 2, Name: "Ball of Fish Paste", Desc: "Fresh fish paste.", Keyword: "seafood"},
+		{ProductID: 3, Name: "Halal Chicken Thigh", Desc: "Fresh chicken.", Keyword: "halal meat"},
+		{ProductID: 4, Name: "Frozen Chicken Wings", Desc: "Chicken wings.", Keyword: "frozen meat"},
+		{ProductID: 5, Name: "Beef Rendang", Desc: "Slow cooked beef.", Keyword: "halal meat"},
+		{ProductID: 6, Name: "Uncle Wong Noodles", Desc: "Thin egg noodles.", Keyword: "dry goods"},
+	})
+
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{name: "Terms are ORed", text: "fish ball", want: []int{1, 2}},
+		{name: "Phrase needs adjacent terms", text: `"fish ball"`, want: []int{1}},
+		{name: "Excluded term", text: "chicken -frozen", want: []int{3}},
+		{name: "Required term", text: "+halal chicken", want: []int{3, 5}},
+		{name: "Required terms", text: "+halal +chicken", want: []int{3}},
+		{name: "Required OR group", text: "+chicken OR +beef -frozen", want: []int{3, 5}},
+		{name: "Only excluded terms", text: "-frozen", want: []int{}},
+		{name: "Stopword in only one language", text: "wong", want: []int{6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := idx.Search(tt.text)
+			if !sameIDs(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+
+	// optional terms still add to the score of required matches
+	_, scores := idx.Search("+halal chicken")
+	if scores[3] <= scores[5] {
+		t.Errorf("product with optional term scored %v, product without scored %v", scores[3], scores[5])
+	}
+}
@@ -3,45 +3,21 @@ package search
 import (
 	"ProjectGoLive/pkg/models"
 	"sort"
-	"sync"
 )
 
-// Search looks through the IndexSlice for matches for
-// every search term provided by the user. If a match is
-// found, the ProductID is inserted into the results map
-// that maps ProductID to relevance score.
-func (idx IndexSlice) Search(text string) ([]int, map[int]int) {
-	var countMap = make(map[int]int)
-	var wg sync.WaitGroup
+// Search looks through the IndexSlice for products matching
+// the query typed by the user. See query for the syntax. The
+// matching ProductIDs are returned along with a map of the
+// ProductID to its BM25 relevance score. A search term that
+// is not found in the IndexSlice is matched against similarly
+// spelt terms instead, at a reduced score.
+func (idx *IndexSlice) Search(text string) ([]int, map[int]float64) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	q := idx.parseQuery(text)
+	countMap := idx.evaluate(q)
 
-	for _, token := range analyze(text) {
-		wg.Add(3)
-		go func() {
-			defer wg.Done()
-			if ids, ok := idx[0][token]; ok {
-				for _, v := range ids {
-					countMap[v] += 2
-				}
-			}
-		}()
-		go func() {
-			defer wg.Done()
-			if ids, ok := idx[1][token]; ok {
-				for _, v := range ids {
-					countMap[v]++
-				}
-			}
-		}()
-		go func() {
-			defer wg.Done()
-			if ids, ok := idx[2][token]; ok {
-				for _, v := range ids {
-					countMap[v] += 4
-				}
-			}
-		}()
-		wg.Wait()
-	}
 	unsorted := []int{}
 
 	for k := range countMap {
@@ -50,10 +26,21 @@ func (idx IndexSlice) Search(text string) ([]int, map[int]int) {
 	return unsorted, countMap
 }
 
+// contains reports whether any field of the IndexSlice
+// has the term. The caller must hold the read lock.
+func (idx *IndexSlice) contains(term string) bool {
+	for _, m := range idx.maps {
+		if _, ok := m[term]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // RankedProduct
 // RankedProduct is called to prepare a list of products
 // for writing to the http response in sorted order.
-func RankedProducts(input []*models.Product, countMap map[int]int) []*models.Product {
+func RankedProducts(input []*models.Product, countMap map[int]float64) []*models.Product {
 	for i := 0; i < len(input); i++ {
 		input[i].Score = countMap[input[i].ProductID]
 	}
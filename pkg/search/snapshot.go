@@ -0,0 +1,227 @@
+package search
+
+import (
+	"encoding/gob"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// snapshotVersion is bumped whenever the layout of the
+// index changes so older snapshots are rebuilt instead of
+// being loaded into an index that cannot read them.
+const snapshotVersion = 2
+
+// ErrSnapshotVersion is returned by Load when the snapshot
+// was saved by a different version of the index.
+var ErrSnapshotVersion = errors.New("search: snapshot version is not supported")
+
+// snapshot is the on-disk form of an IndexSlice. The
+// words map is not saved as it is rebuilt from the docs.
+type snapshot struct {
+	Version  int
+	Synced   time.Time
+	Length   [numFields]int
+	Postings [numFields]map[string][]snapshotPosting
+	Docs     map[int]snapshotDoc
+}
+
+type snapshotPosting struct {
+	ID  int
+	Pos []int
+}
+
+type snapshotDoc struct {
+	Name     string
+	UnitSold int
+	Tokens   [][]string
+	Length   [numFields]int
+	Words    []string
+}
+
+// Save writes the IndexSlice to the file at path. The
+// snapshot is written to a temporary file first and renamed
+// over path, so a crash while saving never leaves a partly
+// written snapshot behind.
+func (idx *IndexSlice) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	idx.mu.RLock()
+	err = gob.NewEncoder(f).Encode(idx.snapshot())
+	idx.mu.RUnlock()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Load replaces the contents of the IndexSlice with the
+// snapshot saved at path. The IndexSlice is left untouched
+// if the snapshot cannot be read. CatchUp should be called
+// after Load to apply changes made since the snapshot.
+func (idx *IndexSlice) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var s snapshot
+	if err = gob.NewDecoder(f).Decode(&s); err != nil {
+		return err
+	}
+	if s.Version != snapshotVersion {
+		return ErrSnapshotVersion
+	}
+
+	fresh := NewIndexSlice()
+	fresh.restore(&s)
+
+	idx.rebuildMu.Lock()
+	defer idx.rebuildMu.Unlock()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.maps, idx.docs, idx.words = fresh.maps, fresh.docs, fresh.words
+	idx.length, idx.synced = fresh.length, fresh.synced
+	idx.completions = fresh.completions
+	return nil
+}
+
+// CatchUp brings the IndexSlice up to date with the database
+// without rebuilding it. modified returns the products modified
+// at or after the latest modification time seen by the index,
+// and ids returns the ProductID of every product, so products
+// deleted since are removed. Like Rebuild, changes made while
+// catching up are replayed so none of them are lost.
+func (idx *IndexSlice) CatchUp(modified func(since time.Time) ([]*models.Product, error), ids func() ([]int, error)) error {
+	idx.rebuildMu.Lock()
+	defer idx.rebuildMu.Unlock()
+
+	idx.mu.Lock()
+	idx.journal = []change{}
+	since := idx.synced
+	idx.mu.Unlock()
+
+	products, err := modified(since)
+	var current []int
+	if err == nil {
+		current, err = ids()
+	}
+	if err != nil {
+		idx.mu.Lock()
+		idx.journal = nil
+		idx.mu.Unlock()
+		return err
+	}
+
+	exists := make(map[int]bool, len(current))
+	for _, id := range current {
+		exists[id] = true
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for id := range idx.docs {
+		if !exists[id] {
+			idx.remove(id)
+		}
+	}
+	for _, product := range products {
+		idx.add(product)
+	}
+	idx.replay(idx)
+	idx.journal = nil
+
+	return nil
+}
+
+// snapshot copies the IndexSlice into its on-disk form.
+// The caller must hold the read lock.
+func (idx *IndexSlice) snapshot() *snapshot {
+	s := &snapshot{
+		Version: snapshotVersion,
+		Synced:  idx.synced,
+		Length:  idx.length,
+		Docs:    make(map[int]snapshotDoc, len(idx.docs)),
+	}
+	for field, m := range idx.maps {
+		s.Postings[field] = make(map[string][]snapshotPosting, len(m))
+		for term, postings := range m {
+			ps := make([]snapshotPosting, len(postings))
+			for i, p := range postings {
+				ps[i] = snapshotPosting{p.id, p.pos}
+			}
+			s.Postings[field][term] = ps
+		}
+	}
+	for id, doc := range idx.docs {
+		s.Docs[id] = snapshotDoc{doc.name, doc.unitSold, doc.tokens, doc.length, doc.words}
+	}
+	return s
+}
+
+// restore fills an empty IndexSlice from a snapshot. The
+// caller must hold the write lock.
+func (idx *IndexSlice) restore(s *snapshot) {
+	idx.synced = s.Synced
+	idx.length = s.Length
+	for field, m := range s.Postings {
+		for term, ps := range m {
+			postings := make([]posting, len(ps))
+			for i, p := range ps {
+				postings[i] = posting{p.ID, p.Pos}
+			}
+			idx.maps[field][term] = postings
+		}
+	}
+	for id, d := range s.Docs {
+		doc := &document{d.Name, d.UnitSold, d.Tokens, d.Length, d.Words}
+		for _, word := range doc.words {
+			stat, ok := idx.words[word]
+			if !ok {
+				stat = &wordStat{}
+				idx.words[word] = stat
+				idx.completions.terms = append(idx.completions.terms, word)
+			}
+			stat.products++
+			stat.unitSold += doc.unitSold
+		}
+		idx.docs[id] = doc
+		idx.completions.names = append(idx.completions.names, nameKeys(doc.name, id)...)
+	}
+
+	// the completions are sorted once they are all added
+	// rather than inserted one at a time
+	c := &idx.completions
+	sort.Strings(c.terms)
+	sort.Slice(c.names, func(i, j int) bool {
+		if c.names[i].key == c.names[j].key {
+			return c.names[i].id < c.names[j].id
+		}
+		return c.names[i].key < c.names[j].key
+	})
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_IndexSlice_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "searchindex.gob")
+
+	idx := newTestIndex()
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewIndexSlice()
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, text := range []string{"rice", `"fish ball"`, "chickn", "+toast -fish"} {
+		wantIDs, wantScores := idx.Search(text)
+		gotIDs, gotScores := loaded.Search(text)
+		if !sameIDs(gotIDs, wantIDs) || !reflect.DeepEqual(gotScores, wantScores) {
+			t.Errorf("Search(%q) after Load = %v %v, want %v %v", text, gotIDs, gotScores, wantIDs, wantScores)
+		}
+	}
+	if got, want := loaded.Complete("fi", 5), idx.Complete("fi", 5); !reflect.DeepEqual(got, want) {
+		t.Errorf("Complete after Load = %v, want %v", got, want)
+	}
+
+	// the loaded index can still be changed
+	loaded.RemoveProduct(4)
+	if got, _ := loaded.Search("fish"); len(got) != 0 {
+		t.Errorf("after RemoveProduct got %v, want none", got)
+	}
+}
+
+func Test_IndexSlice_LoadVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "searchindex.gob")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = gob.NewEncoder(f).Encode(snapshot{Version: snapshotVersion + 1})
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := newTestIndex()
+	if err := idx.Load(path); err != ErrSnapshotVersion {
+		t.Errorf("Load() error = %v, want %v", err, ErrSnapshotVersion)
+	}
+	if got, _ := idx.Search("rice"); !sameIDs(got, []int{1}) {
+		t.Errorf("after failed Load got %v, want %v", got, []int{1})
+	}
+}
+
+func Test_IndexSlice_CatchUp(t *testing.T) {
+	snapshotTime := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Jasmine Rice", Modified: snapshotTime.Add(-time.Hour)},
+		{ProductID: 2, Name: "Kaya Toast", Modified: snapshotTime},
+		{ProductID: 3, Name: "Fish Ball", Modified: snapshotTime.Add(-2 * time.Hour)},
+	})
+
+	// product 2 was renamed, product 3 deleted and product 4 added
+	var since time.Time
+	modified := func(t time.Time) ([]*models.Product, error) {
+		since = t
+		return []*models.Product{
+			{ProductID: 2, Name: "Kopi", Modified: snapshotTime.Add(time.Minute)},
+			{ProductID: 4, Name: "Durian", Modified: snapshotTime.Add(2 * time.Minute)},
+		}, nil
+	}
+	ids := func() ([]int, error) { return []int{1, 2, 4}, nil }
+
+	if err := idx.CatchUp(modified, ids); err != nil {
+		t.Fatal(err)
+	}
+	if !since.Equal(snapshotTime) {
+		t.Errorf("CatchUp asked for products modified since %v, want %v", since, snapshotTime)
+	}
+	for text, want := range map[string][]int{"rice": {1}, "toast": {}, "kopi": {2}, "fish": {}, "durian": {4}} {
+		if got, _ := idx.Search(text); !sameIDs(got, want) {
+			t.Errorf("after CatchUp Search(%q) = %v, want %v", text, got, want)
+		}
+	}
+
+	// the next catch up starts from the newest product seen
+	idx.CatchUp(modified, ids)
+	if want := snapshotTime.Add(2 * time.Minute); !since.Equal(want) {
+		t.Errorf("second CatchUp asked for products modified since %v, want %v", since, want)
+	}
+
+	// a failed catch up leaves the index untouched
+	errDB := errors.New("database is down")
+	err := idx.CatchUp(modified, func() ([]int, error) { return nil, errDB })
+	if err != errDB {
+		t.Errorf("CatchUp() error = %v, want %v", err, errDB)
+	}
+	if got, _ := idx.Search("durian"); !sameIDs(got, []int{4}) {
+		t.Errorf("after failed CatchUp got %v, want %v", got, []int{4})
+	}
+}
@@ -0,0 +1,178 @@
+package search
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// Fragment is a piece of a Snippet. Match is set if the
+// fragment is a search term found in the product.
+type Fragment struct {
+	Text  string
+	Match bool
+}
+
+// Snippet is a short part of the name or description of a
+// product, broken into fragments so the search terms found
+// in it can be highlighted.
+type Snippet []Fragment
+
+// ellipsis marks where a description was cut for a snippet.
+const ellipsis = "…"
+
+// Snippets returns a Snippet for each of the products,
+// mapped to their ProductID, showing why they matched the
+// search text. The name of a product is used if a search
+// term was found in it, otherwise up to size characters
+// of the description around the first search term found.
+func (idx *IndexSlice) Snippets(text string, products []*models.Product, size int) map[int]Snippet {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	terms := idx.highlightTerms(idx.parseQuery(text))
+
+	snippets := make(map[int]Snippet, len(products))
+	for _, p := range products {
+		fields := idx.language(p).Fields
+		if ranges := matchRanges(p.Name, fields.Name, terms); len(ranges) > 0 {
+			snippets[p.ProductID] = fragments(p.Name, ranges, 0, len(p.Name))
+			continue
+		}
+
+		ranges := matchRanges(p.Desc, fields.Desc, terms)
+		from := 0
+		if len(ranges) > 0 {
+			from = ranges[0][0]
+		}
+		start, end := window(p.Desc, from, size)
+		snippets[p.ProductID] = fragments(p.Desc, ranges, start, end)
+	}
+	return snippets
+}
+
+// highlightTerms returns the terms a product can have
+// matched the query with, leaving out excluded terms. The
+// caller must hold the read lock.
+func (idx *IndexSlice) highlightTerms(q query) map[string]bool {
+	terms := map[string]bool{}
+	for _, g := range q.groups {
+		if g.occur == mustNot {
+			continue
+		}
+		for _, c := range g.clauses {
+			for _, analysis := range append(append([][]string{c.terms}, c.alts...), c.synonyms...) {
+				for _, term := range analysis {
+					terms[term] = true
+				}
+				if len(analysis) == 1 && !idx.contains(analysis[0]) {
+					for candidate := range idx.fuzzyTerms(analysis[0]) {
+						terms[candidate] = true
+					}
+				}
+			}
+		}
+	}
+	return terms
+}
+
+// matchRanges returns the byte offsets of the parts of the
+// text the analyzer finds any of the terms in. Overlapping
+// parts, such as Chinese bigrams, are merged.
+func matchRanges(text string, a Analyzer, terms map[string]bool) [][2]int {
+	var ranges [][2]int
+	for _, token := range a.Analyze(text) {
+		if !terms[token.Term] {
+			continue
+		}
+		if n := len(ranges); n > 0 && token.Start < ranges[n-1][1] {
+			if token.End > ranges[n-1][1] {
+				ranges[n-1][1] = token.End
+			}
+			continue
+		}
+		ranges = append(ranges, [2]int{token.Start, token.End})
+	}
+	return ranges
+}
+
+// window returns the byte offsets of up to size characters
+// of the text, starting a little before from so the match
+// there is shown with some of the words before it. The
+// window is cut at spaces where possible.
+func window(text string, from, size int) (int, int) {
+	start := back(text, from, size/4)
+	end := start
+	for n := 0; end < len(text) && n < size; n++ {
+		_, w := utf8.DecodeRuneInString(text[end:])
+		end += w
+	}
+	// near the end of the text, fill the window
+	// with more of the words before the match
+	if end == len(text) {
+		start = back(text, end, size)
+		if start > from {
+			start = from
+		}
+	}
+
+	// start and end on whole words
+	if start > 0 && text[start-1] != ' ' {
+		if i := strings.IndexByte(text[start:from], ' '); i >= 0 {
+			start += i + 1
+		}
+	}
+	if end < len(text) {
+		if i := strings.LastIndexByte(text[start:end], ' '); i > from-start {
+			end = start + i
+		}
+	}
+	return start, end
+}
+
+// back returns the offset n characters before the
+// offset i of the text, or 0.
+func back(text string, i, n int) int {
+	for ; i > 0 && n > 0; n-- {
+		_, w := utf8.DecodeLastRuneInString(text[:i])
+		i -= w
+	}
+	return i
+}
+
+// fragments breaks the text between start and end into
+// fragments, marking the ranges as matches.
+func fragments(text string, ranges [][2]int, start, end int) Snippet {
+	s := Snippet{}
+	if start > 0 {
+		s = append(s, Fragment{Text: ellipsis})
+	}
+
+	pos := start
+	for _, r := range ranges {
+		a, b := r[0], r[1]
+		if b <= pos || a >= end {
+			continue
+		}
+		if a < pos {
+			a = pos
+		}
+		if b > end {
+			b = end
+		}
+		if a > pos {
+			s = append(s, Fragment{Text: text[pos:a]})
+		}
+		s = append(s, Fragment{Text: text[a:b], Match: true})
+		pos = b
+	}
+	if pos < end {
+		s = append(s, Fragment{Text: text[pos:end]})
+	}
+
+	if end < len(text) {
+		s = append(s, Fragment{Text: ellipsis})
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"strings"
+	"testing"
+)
+
+// render writes a snippet with the matches in brackets.
+func render(s Snippet) string {
+	var b strings.Builder
+	for _, f := range s {
+		if f.Match {
+			b.WriteString("[" + f.Text + "]")
+			continue
+		}
+		b.WriteString(f.Text)
+	}
+	return b.String()
+}
+
+func Test_IndexSlice_Snippets(t *testing.T) {
+	long := "Our kaya is cooked slowly over a low fire for many hours, stirred by hand " +
+		"the whole time, and bottled while warm. Spread it thick on toast with a slab of cold butter."
+	ps := []*models.Product{
+		{ProductID: 1, Name: "Fresh Milk", Desc: "Full cream milk."},
+		{ProductID: 2, Name: "Kaya Jam", Desc: long},
+		{ProductID: 3, Name: "上海白菜", Desc: "新鲜白菜"},
+		{ProductID: 4, Name: "Tiger Prawns", Desc: "Fresh prawns."},
+	}
+	idx := NewIndexSlice()
+	idx.Add(ps)
+	s, _ := ParseSynonyms(strings.NewReader("prawn, shrimp"))
+	idx.SetSynonyms(s)
+
+	tests := []struct {
+		name string
+		text string
+		id   int
+		size int
+		want string
+	}{
+		{name: "Match in name", text: "milk", id: 1, size: 40, want: "Fresh [Milk]"},
+		{name: "Stemmed match", text: "milking", id: 1, size: 40, want: "Fresh [Milk]"},
+		{name: "Match in description", text: "cream", id: 1, size: 40, want: "Full [cream] milk."},
+		{name: "Description cut around the match", text: "butter", id: 2, size: 40, want: "…on toast with a slab of cold [butter]."},
+		{name: "Description cut after the match", text: "stirred", id: 2, size: 30, want: "…hours, [stirred] by hand the…"},
+		{name: "No match", text: "coconut", id: 2, size: 20, want: "Our kaya is cooked…"},
+		{name: "Excluded terms are not highlighted", text: "kaya -jam", id: 2, size: 40, want: "[Kaya] Jam"},
+		{name: "Chinese bigrams", text: "白菜", id: 3, size: 40, want: "上海[白菜]"},
+		{name: "Synonym", text: "shrimp", id: 4, size: 40, want: "Tiger [Prawns]"},
+		{name: "Misspelt term", text: "prawnz", id: 4, size: 40, want: "Tiger [Prawns]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p []*models.Product
+			for _, product := range ps {
+				if product.ProductID == tt.id {
+					p = append(p, product)
+				}
+			}
+			got := render(idx.Snippets(tt.text, p, tt.size)[tt.id])
+			if got != tt.want {
+				t.Errorf("Snippets(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Correction is a search with its misspelt words replaced
+// by words found in the IndexSlice. Confidence is between 0
+// and 1, and is higher the closer the replacements are to
+// the words typed and the more popular they are compared
+// to the other words they could have been.
+type Correction struct {
+	Text       string
+	Confidence float64
+}
+
+// Correct suggests a correction for the search text, for
+// showing as "Did you mean" when a search finds no or very
+// few products. Every word of the search which is not in
+// the IndexSlice is replaced by the word within maxEdits of
+// it found in the most product names and keywords. Correct
+// returns false if none of the words could be corrected.
+func (idx *IndexSlice) Correct(text string) (Correction, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var b strings.Builder
+	confidence := 1.0
+	corrected := false
+
+	pos := 0
+	for _, token := range WordTokenizer(text) {
+		word := strings.ToLower(token.Term)
+		if excluded(text, token.Start) || !hasLetter(word) || isStopword("en", word) || idx.known(word) {
+			continue
+		}
+
+		replacement, c := idx.correctWord(word)
+		if replacement == "" {
+			continue
+		}
+		b.WriteString(text[pos:token.Start])
+		b.WriteString(replacement)
+		pos = token.End
+		confidence *= c
+		corrected = true
+	}
+	if !corrected {
+		return Correction{}, false
+	}
+	b.WriteString(text[pos:])
+
+	return Correction{Text: b.String(), Confidence: confidence}, true
+}
+
+// candidate is a word which a misspelt word may have been.
+type candidate struct {
+	word     string
+	distance int
+	weight   float64
+}
+
+// correctWord returns the word in the vocabulary of the
+// IndexSlice which the misspelt word most likely was, and
+// the confidence in it, or "" if no word is close enough.
+// The caller must hold the read lock.
+func (idx *IndexSlice) correctWord(word string) (string, float64) {
+	max := maxEdits(word)
+	if max == 0 {
+		return "", 0
+	}
+
+	// every word close enough is weighted by the number of
+	// products it is found in, scaled down for every edit
+	candidates := []candidate{}
+	total := 0.0
+	for w, stat := range idx.words {
+		d := editDistance(word, w, max)
+		if d > max || d == 0 {
+			continue
+		}
+		weight := float64(stat.products)
+		for i := 0; i < d; i++ {
+			weight *= fuzzyPenalty
+		}
+		candidates = append(candidates, candidate{word: w, distance: d, weight: weight})
+		total += weight
+	}
+	if len(candidates) == 0 {
+		return "", 0
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].weight != candidates[j].weight {
+			return candidates[i].weight > candidates[j].weight
+		}
+		return candidates[i].word < candidates[j].word
+	})
+	best := candidates[0]
+
+	// the share of the weight taken by the best word, less
+	// the share of the word changed to get to it
+	closeness := 1 - float64(best.distance)/float64(utf8.RuneCountInString(word))
+	return best.word, best.weight / total * closeness
+}
+
+// known reports whether the word is found in the IndexSlice
+// when analyzed in any of its languages. The caller must hold
+// the read lock.
+func (idx *IndexSlice) known(word string) bool {
+	if _, ok := idx.words[word]; ok {
+		return true
+	}
+	for _, lang := range idx.languages {
+		for field := 0; field < numFields; field++ {
+			terms := termsOf(lang.Fields.analyzer(field).Analyze(word))
+			if len(terms) == 0 {
+				continue
+			}
+			found := true
+			for _, term := range terms {
+				if !idx.contains(term) {
+					found = false
+					break
+				}
+			}
+			if found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excluded reports whether the word starting at the offset
+// i of the search text is excluded with a minus sign.
+func excluded(text string, i int) bool {
+	return i > 0 && text[i-1] == '-' && (i == 1 || text[i-2] == ' ')
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"testing"
+)
+
+func Test_IndexSlice_Correct(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Musang King Durian", Desc: "Creamy and bittersweet.", Keyword: "durian"},
+		{ProductID: 2, Name: "D24 Durian", Desc: "Sweet durian flesh.", Keyword: "durian"},
+		{ProductID: 3, Name: "Durio Puree", Desc: "Durian puree for baking.", Keyword: "puree"},
+		{ProductID: 4, Name: "Frozen Chicken Wings", Desc: "Chicken wings.", Keyword: "chicken"},
+	})
+
+	tests := []struct {
+		name string
+		text string
+		want string
+		ok   bool
+	}{
+		{name: "Misspelt word", text: "duriam", want: "durian", ok: true},
+		{name: "Other words are kept", text: "Fresh duriam!", want: "Fresh durian!", ok: true},
+		{name: "Several misspelt words", text: "chiken wingz", want: "chicken wings", ok: true},
+		{name: "Stemmed word is known", text: "chickens", ok: false},
+		{name: "Word in a description is known", text: "creamy", ok: false},
+		{name: "Excluded word", text: "chicken -duriam", ok: false},
+		{name: "Short word", text: "dur", ok: false},
+		{name: "Nothing close", text: "coconut", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := idx.Correct(tt.text)
+			if ok != tt.ok || got.Text != tt.want {
+				t.Errorf("Correct(%q) = %q, %v, want %q, %v", tt.text, got.Text, ok, tt.want, tt.ok)
+			}
+			if ok && (got.Confidence <= 0 || got.Confidence > 1) {
+				t.Errorf("Correct(%q) confidence = %v, want between 0 and 1", tt.text, got.Confidence)
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_CorrectConfidence(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Durian", Keyword: "durian"},
+		{ProductID: 2, Name: "Durian Cake", Keyword: "durian"},
+		{ProductID: 3, Name: "Durio Puree", Keyword: "puree"},
+	})
+
+	// durian is in more products than durio, and
+	// both are a single edit away from durin
+	popular, _ := idx.Correct("durin")
+	if popular.Text != "durian" {
+		t.Fatalf("Correct(%q) = %q, want %q", "durin", popular.Text, "durian")
+	}
+	// durio is the only word close to durioo
+	only, _ := idx.Correct("durioo")
+	if only.Text != "durio" {
+		t.Fatalf("Correct(%q) = %q, want %q", "durioo", only.Text, "durio")
+	}
+	if popular.Confidence >= only.Confidence {
+		t.Errorf("confidence with a close alternative %v, want less than %v", popular.Confidence, only.Confidence)
+	}
+}
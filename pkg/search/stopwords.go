@@ -0,0 +1,213 @@
+package search
+
+// stopwords maps the ISO 639-1 code of a language to its
+// stopwords, so a token is checked with a single lookup.
+// Both lists are taken from github.com/bbalet/stopwords,
+// which is under the BSD license; the English list is by
+// Jacques Savoy.
+var stopwords = map[string]map[string]bool{
+	"en": {
+		"a": true, "about": true, "above": true, "across": true,
+		"after": true, "afterwards": true, "again": true,
+		"against": true, "all": true, "almost": true, "alone": true,
+		"along": true, "already": true, "also": true,
+		"although": true, "always": true, "am": true, "among": true,
+		"amongst": true, "amoungst": true, "amount": true,
+		"an": true, "and": true, "another": true, "any": true,
+		"anyhow": true, "anyone": true, "anything": true,
+		"anyway": true, "anywhere": true, "are": true,
+		"around": true, "as": true, "at": true, "back": true,
+		"be": true, "became": true, "because": true, "become": true,
+		"becomes": true, "becoming": true, "been": true,
+		"before": true, "beforehand": true, "behind": true,
+		"being": true, "below": true, "beside": true,
+		"besides": true, "between": true, "beyond": true,
+		"bill": true, "both": true, "bottom": true, "but": true,
+		"by": true, "call": true, "can": true, "cannot": true,
+		"cant": true, "co": true, "con": true, "could": true,
+		"couldnt": true, "cry": true, "de": true, "describe": true,
+		"detail": true, "do": true, "done": true, "down": true,
+		"due": true, "during": true, "each": true, "eg": true,
+		"eight": true, "either": true, "eleven": true, "else": true,
+		"elsewhere": true, "empty": true, "enough": true,
+		"etc": true, "even": true, "ever": true, "every": true,
+		"everyone": true, "everything": true, "everywhere": true,
+		"except": true, "few": true, "fifteen": true, "fify": true,
+		"fill": true, "find": true, "fire": true, "first": true,
+		"five": true, "for": true, "former": true, "formerly": true,
+		"forty": true, "found": true, "four": true, "from": true,
+		"front": true, "full": true, "further": true, "get": true,
+		"give": true, "go": true, "had": true, "has": true,
+		"hasnt": true, "have": true, "he": true, "hence": true,
+		"her": true, "here": true, "hereafter": true,
+		"hereby": true, "herein": true, "hereupon": true,
+		"hers": true, "herself": true, "him": true, "himself": true,
+		"his": true, "how": true, "however": true, "hundred": true,
+		"ie": true, "if": true, "in": true, "inc": true,
+		"indeed": true, "interest": true, "into": true, "is": true,
+		"it": true, "its": true, "itself": true, "keep": true,
+		"last": true, "latter": true, "latterly": true,
+		"least": true, "less": true, "ltd": true, "made": true,
+		"many": true, "may": true, "me": true, "meanwhile": true,
+		"might": true, "mill": true, "mine": true, "more": true,
+		"moreover": true, "most": true, "mostly": true,
+		"move": true, "much": true, "must": true, "my": true,
+		"myself": true, "name": true, "namely": true,
+		"neither": true, "never": true, "nevertheless": true,
+		"next": true, "nine": true, "no": true, "nobody": true,
+		"none": true, "noone": true, "nor": true, "not": true,
+		"nothing": true, "now": true, "nowhere": true, "of": true,
+		"off": true, "often": true, "on": true, "once": true,
+		"one": true, "only": true, "onto": true, "or": true,
+		"other": true, "others": true, "otherwise": true,
+		"our": true, "ours": true, "ourselves": true, "out": true,
+		"over": true, "own": true, "part": true, "per": true,
+		"perhaps": true, "please": true, "put": true,
+		"rather": true, "re": true, "same": true, "see": true,
+		"seem": true, "seemed": true, "seeming": true,
+		"seems": true, "serious": true, "several": true,
+		"she": true, "should": true, "show": true, "side": true,
+		"since": true, "sincere": true, "six": true, "sixty": true,
+		"so": true, "some": true, "somehow": true, "someone": true,
+		"something": true, "sometime": true, "sometimes": true,
+		"somewhere": true, "still": true, "such": true,
+		"system": true, "take": true, "ten": true, "than": true,
+		"that": true, "the": true, "their": true, "them": true,
+		"themselves": true, "then": true, "thence": true,
+		"there": true, "thereafter": true, "thereby": true,
+		"therefore": true, "therein": true, "thereupon": true,
+		"these": true, "they": true, "thickv": true, "thin": true,
+		"third": true, "this": true, "those": true, "though": true,
+		"three": true, "through": true, "throughout": true,
+		"thru": true, "thus": true, "to": true, "together": true,
+		"too": true, "top": true, "toward": true, "towards": true,
+		"twelve": true, "twenty": true, "two": true, "un": true,
+		"under": true, "until": true, "up": true, "upon": true,
+		"us": true, "very": true, "via": true, "was": true,
+		"we": true, "well": true, "were": true, "what": true,
+		"whatever": true, "when": true, "whence": true,
+		"whenever": true, "where": true, "whereafter": true,
+		"whereas": true, "whereby": true, "wherein": true,
+		"whereupon": true, "wherever": true, "whether": true,
+		"which": true, "while": true, "whither": true, "who": true,
+		"whoever": true, "whole": true, "whom": true, "whose": true,
+		"why": true, "will": true, "with": true, "within": true,
+		"without": true, "would": true, "yet": true, "you": true,
+		"your": true, "yours": true, "yourself": true,
+		"yourselves": true,
+	},
+	"id": {
+		"ada": true, "adalah": true, "adanya": true, "adapun": true,
+		"agak": true, "agaknya": true, "agar": true, "akan": true,
+		"akankah": true, "akhirnya": true, "aku": true,
+		"akulah": true, "amat": true, "amatlah": true, "anda": true,
+		"andalah": true, "antar": true, "antara": true,
+		"antaranya": true, "apa": true, "apaan": true,
+		"apabila": true, "apakah": true, "apalagi": true,
+		"apatah": true, "atau": true, "ataukah": true,
+		"ataupun": true, "bagai": true, "bagaikan": true,
+		"bagaimana": true, "bagaimanakah": true,
+		"bagaimanapun": true, "bagi": true, "bahkan": true,
+		"bahwa": true, "bahwasanya": true, "banyak": true,
+		"beberapa": true, "begini": true, "beginian": true,
+		"beginikah": true, "beginilah": true, "begitu": true,
+		"begitukah": true, "begitulah": true, "begitupun": true,
+		"belum": true, "belumlah": true, "berapa": true,
+		"berapakah": true, "berapalah": true, "berapapun": true,
+		"bermacam": true, "bersama": true, "betulkah": true,
+		"biasa": true, "biasanya": true, "bila": true,
+		"bilakah": true, "bisa": true, "bisakah": true,
+		"boleh": true, "bolehkah": true, "bolehlah": true,
+		"buat": true, "bukan": true, "bukankah": true,
+		"bukanlah": true, "bukannya": true, "cuma": true,
+		"dahulu": true, "dalam": true, "dan": true, "dapat": true,
+		"dari": true, "daripada": true, "dekat": true, "demi": true,
+		"demikian": true, "demikianlah": true, "dengan": true,
+		"depan": true, "di": true, "dia": true, "dialah": true,
+		"diantara": true, "diantaranya": true, "dikarenakan": true,
+		"dini": true, "diri": true, "dirinya": true, "disini": true,
+		"disinilah": true, "dong": true, "dulu": true,
+		"enggak": true, "enggaknya": true, "entah": true,
+		"entahlah": true, "hal": true, "hampir": true,
+		"hanya": true, "hanyalah": true, "harus": true,
+		"haruslah": true, "harusnya": true, "hendak": true,
+		"hendaklah": true, "hendaknya": true, "hingga": true,
+		"ia": true, "ialah": true, "ibarat": true, "ingin": true,
+		"inginkah": true, "inginkan": true, "ini": true,
+		"inikah": true, "inilah": true, "itu": true, "itukah": true,
+		"itulah": true, "jangan": true, "jangankan": true,
+		"janganlah": true, "jika": true, "jikalau": true,
+		"juga": true, "justru": true, "kala": true, "kalau": true,
+		"kalaulah": true, "kalaupun": true, "kalian": true,
+		"kami": true, "kamilah": true, "kamu": true,
+		"kamulah": true, "kan": true, "kapan": true,
+		"kapankah": true, "kapanpun": true, "karena": true,
+		"karenanya": true, "ke": true, "kecil": true,
+		"kemudian": true, "kenapa": true, "kepada": true,
+		"kepadanya": true, "ketika": true, "khususnya": true,
+		"kini": true, "kinilah": true, "kiranya": true,
+		"kita": true, "kitalah": true, "kok": true, "lagi": true,
+		"lagian": true, "lah": true, "lain": true, "lainnya": true,
+		"lalu": true, "lama": true, "lamanya": true, "lebih": true,
+		"macam": true, "maka": true, "makanya": true, "makin": true,
+		"malah": true, "malahan": true, "mampu": true,
+		"mampukah": true, "mana": true, "manakala": true,
+		"manalagi": true, "masih": true, "masihkah": true,
+		"masing": true, "mau": true, "maupun": true,
+		"melainkan": true, "melalui": true, "memang": true,
+		"mengapa": true, "mereka": true, "merekalah": true,
+		"merupakan": true, "meski": true, "meskipun": true,
+		"mungkin": true, "mungkinkah": true, "nah": true,
+		"namun": true, "nanti": true, "nantinya": true,
+		"nyaris": true, "oleh": true, "olehnya": true, "pada": true,
+		"padahal": true, "padanya": true, "paling": true,
+		"pantas": true, "para": true, "pasti": true,
+		"pastilah": true, "per": true, "percuma": true,
+		"pernah": true, "pula": true, "pun": true, "rupanya": true,
+		"saat": true, "saatnya": true, "saja": true,
+		"sajalah": true, "saling": true, "sama": true,
+		"sambil": true, "sampai": true, "sana": true,
+		"sangat": true, "sangatlah": true, "saya": true,
+		"sayalah": true, "se": true, "sebab": true,
+		"sebabnya": true, "sebagai": true, "sebagaimana": true,
+		"sebagainya": true, "sebaliknya": true, "sebanyak": true,
+		"sebegini": true, "sebegitu": true, "sebelum": true,
+		"sebelumnya": true, "sebenarnya": true, "seberapa": true,
+		"sebetulnya": true, "sebisanya": true, "sebuah": true,
+		"sedang": true, "sedangkan": true, "sedemikian": true,
+		"sedikit": true, "sedikitnya": true, "segala": true,
+		"segalanya": true, "segera": true, "seharusnya": true,
+		"sehingga": true, "sejak": true, "sejenak": true,
+		"sekali": true, "sekalian": true, "sekaligus": true,
+		"sekalipun": true, "sekarang": true, "seketika": true,
+		"sekiranya": true, "sekitar": true, "sekitarnya": true,
+		"sela": true, "selagi": true, "selain": true,
+		"selaku": true, "selalu": true, "selama": true,
+		"selamanya": true, "seluruh": true, "seluruhnya": true,
+		"semacam": true, "semakin": true, "semasih": true,
+		"semaunya": true, "sementara": true, "sempat": true,
+		"semua": true, "semuanya": true, "semula": true,
+		"sendiri": true, "sendirinya": true, "seolah": true,
+		"seorang": true, "sepanjang": true, "sepantasnya": true,
+		"sepantasnyalah": true, "seperti": true, "sepertinya": true,
+		"sering": true, "seringnya": true, "serta": true,
+		"serupa": true, "sesaat": true, "sesama": true,
+		"sesegera": true, "sesekali": true, "seseorang": true,
+		"sesuatu": true, "sesuatunya": true, "sesudah": true,
+		"sesudahnya": true, "setelah": true, "seterusnya": true,
+		"setiap": true, "setidaknya": true, "sewaktu": true,
+		"siapa": true, "siapakah": true, "siapapun": true,
+		"sini": true, "sinilah": true, "suatu": true, "sudah": true,
+		"sudahkah": true, "sudahlah": true, "supaya": true,
+		"tadi": true, "tadinya": true, "tak": true, "tanpa": true,
+		"tapi": true, "telah": true, "tentang": true, "tentu": true,
+		"tentulah": true, "tentunya": true, "terdiri": true,
+		"terhadap": true, "terhadapnya": true, "terlalu": true,
+		"terlebih": true, "tersebut": true, "tersebutlah": true,
+		"tertentu": true, "tetapi": true, "tiap": true,
+		"tidak": true, "tidakkah": true, "tidaklah": true,
+		"toh": true, "waduh": true, "wah": true, "wahai": true,
+		"walau": true, "walaupun": true, "wong": true,
+		"yaitu": true, "yakni": true, "yang": true,
+	},
+}
@@ -0,0 +1,171 @@
+package search
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// synonymWeight is the weight given to a product matched by
+// a synonym of a search term, so products matching the term
+// itself rank higher.
+const synonymWeight = 0.7
+
+// Synonyms is a dictionary of groups of search terms which
+// mean the same thing, such as prawn and shrimp.
+type Synonyms struct {
+	groups [][]string
+}
+
+// ParseSynonyms reads a synonym dictionary. Each line holds
+// a group of comma separated synonyms, which may be more
+// than one word:
+//
+//	# seafood
+//	prawn, shrimp, udang
+//	chilli, chili, cili padi
+//
+// Blank lines and lines starting with # are ignored.
+func ParseSynonyms(r io.Reader) (*Synonyms, error) {
+	s := &Synonyms{}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		group := []string{}
+		for _, synonym := range strings.Split(text, ",") {
+			synonym = strings.Join(termsOf(WordTokenizer(synonym)), " ")
+			if synonym != "" {
+				group = append(group, synonym)
+			}
+		}
+		if len(group) < 2 {
+			return nil, fmt.Errorf("search: line %d of synonyms needs at least two synonyms", line)
+		}
+		s.groups = append(s.groups, group)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LoadSynonyms reads the synonym dictionary in the file at
+// path. See ParseSynonyms for the format.
+func LoadSynonyms(path string) (*Synonyms, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseSynonyms(f)
+}
+
+// SetSynonyms changes the synonyms searches are expanded
+// with. A nil Synonyms turns off synonym expansion.
+func (idx *IndexSlice) SetSynonyms(s *Synonyms) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.synonyms = s
+	idx.compileSynonyms()
+}
+
+// compileSynonyms analyzes the synonyms in every language
+// of the index, mapping every analysis of a synonym to the
+// analyses of the other synonyms in its group. The caller
+// must hold the write lock.
+func (idx *IndexSlice) compileSynonyms() {
+	idx.synonymTerms = map[string][][]string{}
+	idx.synonymWords = 0
+	if idx.synonyms == nil {
+		return
+	}
+
+	for _, group := range idx.synonyms.groups {
+		analyses := make([][][]string, len(group))
+		for i, synonym := range group {
+			for _, lang := range idx.languages {
+				terms := termsOf(lang.Fields.Name.Analyze(synonym))
+				if len(terms) > 0 && !containsTerms(analyses[i], terms) {
+					analyses[i] = append(analyses[i], terms)
+				}
+			}
+			if n := len(strings.Fields(synonym)); n > idx.synonymWords {
+				idx.synonymWords = n
+			}
+		}
+
+		for i := range group {
+			for _, terms := range analyses[i] {
+				key := strings.Join(terms, " ")
+				for j := range group {
+					if j == i {
+						continue
+					}
+					for _, other := range analyses[j] {
+						if !sameTerms(other, terms) && !containsTerms(idx.synonymTerms[key], other) {
+							idx.synonymTerms[key] = append(idx.synonymTerms[key], other)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// synonymSpan returns how many of the words, from the
+// first, make up the longest synonym in the dictionary,
+// or 1 if they do not start with a synonym of more than
+// one word. The caller must hold the read lock.
+func (idx *IndexSlice) synonymSpan(words []queryWord, languages []Language) int {
+	for n := idx.synonymWords; n > 1; n-- {
+		if n > len(words) {
+			continue
+		}
+
+		raws := make([]string, n)
+		plain := true
+		for i, w := range words[:n] {
+			if w.quoted || (i > 0 && w.occur != should) || w.raw == "OR" {
+				plain = false
+				break
+			}
+			raws[i] = w.raw
+		}
+		if !plain {
+			continue
+		}
+
+		text := strings.Join(raws, " ")
+		for _, lang := range languages {
+			terms := termsOf(lang.Fields.Name.Analyze(text))
+			if _, ok := idx.synonymTerms[strings.Join(terms, " ")]; ok && len(terms) > 0 {
+				return n
+			}
+		}
+	}
+	return 1
+}
+
+// synonymsOf returns the analyzed synonyms of every analysis
+// of the clause. The caller must hold the read lock.
+func (idx *IndexSlice) synonymsOf(c clause) [][]string {
+	var synonyms [][]string
+	for _, terms := range append([][]string{c.terms}, c.alts...) {
+		for _, other := range idx.synonymTerms[strings.Join(terms, " ")] {
+			if sameTerms(other, c.terms) || containsTerms(c.alts, other) || containsTerms(synonyms, other) {
+				continue
+			}
+			synonyms = append(synonyms, other)
+		}
+	}
+	return synonyms
+}
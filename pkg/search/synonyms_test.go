@@ -0,0 +1,93 @@
+package search
+
+import (
+	"ProjectGoLive/pkg/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ParseSynonyms(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name: "Groups",
+			text: "# seafood\nprawn, shrimp\n\nchilli,chili , cili  padi\n",
+			want: [][]string{{"prawn", "shrimp"}, {"chilli", "chili", "cili padi"}},
+		},
+		{
+			name:    "Single synonym",
+			text:    "prawn, shrimp\nchilli,\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSynonyms(strings.NewReader(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSynonyms() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got.groups, tt.want) {
+				t.Errorf("ParseSynonyms() = %q, want %q", got.groups, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IndexSlice_SearchSynonyms(t *testing.T) {
+	idx := NewIndexSlice()
+	idx.Add([]*models.Product{
+		{ProductID: 1, Name: "Tiger Prawns", Desc: "Fresh prawns.", Keyword: "seafood"},
+		{ProductID: 2, Name: "Frozen Shrimp", Desc: "Peeled shrimp.", Keyword: "seafood"},
+		{ProductID: 3, Name: "Chilli Sauce", Desc: "Sweet chilli sauce.", Keyword: "sauce"},
+		{ProductID: 4, Name: "Cili Padi Segar", Desc: "Cili padi.", Keyword: "sayur"},
+		{ProductID: 5, Name: "Padi Straw Mushroom", Desc: "Mushrooms.", Keyword: "vegetables"},
+	})
+	s, err := ParseSynonyms(strings.NewReader("prawn, shrimp\nchilli, chili, cili padi\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx.SetSynonyms(s)
+
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{name: "Synonym", text: "prawn", want: []int{1, 2}},
+		{name: "Synonym of a plural", text: "shrimps", want: []int{1, 2}},
+		{name: "Synonym of more than one word", text: "chili", want: []int{3, 4}},
+		{name: "Words of a synonym are read together", text: "cili padi", want: []int{3, 4}},
+		{name: "Excluded synonym", text: "seafood -shrimp", want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := idx.Search(tt.text)
+			if !sameIDs(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+
+	// exact matches rank above synonym matches
+	_, scores := idx.Search("prawn")
+	if scores[1] <= scores[2] {
+		t.Errorf("exact match scored %v, synonym match scored %v", scores[1], scores[2])
+	}
+	_, scores = idx.Search("shrimp")
+	if scores[2] <= scores[1] {
+		t.Errorf("exact match scored %v, synonym match scored %v", scores[2], scores[1])
+	}
+
+	// synonyms can be turned off
+	idx.SetSynonyms(nil)
+	if got, _ := idx.Search("prawn"); !sameIDs(got, []int{1}) {
+		t.Errorf("without synonyms Search(%q) = %v, want %v", "prawn", got, []int{1})
+	}
+}
@@ -0,0 +1,95 @@
+package sort
+
+import "ProjectGoLive/pkg/models"
+
+// algorithm is one of the ways Sort can sort the data.
+type algorithm int
+
+const (
+	// the data is already sorted
+	sorted algorithm = iota
+	// the data is sorted in reverse, with no ties
+	reversed
+	insertion
+	tim
+	intro
+	parallel
+)
+
+// nearlySorted is the fewest elements for each element out
+// of order that data is nearly sorted with, such as after the
+// inventory of a few products changes. TimSort is used for
+// nearly sorted data since its runs are already in order.
+const nearlySorted = 16
+
+// Sort sorts the products by the spec, choosing the algorithm
+// from the number of products and how much of them is already
+// in order. Sort is stable, so products the spec ranks equal
+// keep their order, such as their relevance to a search.
+func Sort(data []*models.Product, spec Spec) {
+	SortStableFunc(data, spec.Less())
+}
+
+// SortFunc sorts the data with lt, choosing the algorithm
+// like Sort. SortFunc is not stable.
+func SortFunc[T any](data []T, lt Less[T]) {
+	sortWith(choose(data, lt, false), data, lt)
+}
+
+// SortStableFunc sorts the data with lt, choosing the
+// algorithm like Sort. SortStableFunc is stable.
+func SortStableFunc[T any](data []T, lt Less[T]) {
+	sortWith(choose(data, lt, true), data, lt)
+}
+
+// choose returns the algorithm best suited to sorting the
+// data with lt. Only stable algorithms are chosen if stable
+// is set.
+func choose[T any](data []T, lt Less[T], stable bool) algorithm {
+	n := len(data)
+	if n <= run {
+		return insertion
+	}
+
+	// count the elements out of order
+	descents := 0
+	for i := 1; i < n; i++ {
+		if lt(data[i], data[i-1]) {
+			descents++
+		}
+	}
+
+	switch {
+	case descents == 0:
+		return sorted
+	case descents == n-1:
+		return reversed
+	case descents*nearlySorted < n:
+		return tim
+	case n >= parallelThreshold:
+		return parallel
+	case stable:
+		return tim
+	default:
+		return intro
+	}
+}
+
+// sortWith sorts the data with lt using the algorithm.
+func sortWith[T any](a algorithm, data []T, lt Less[T]) {
+	switch a {
+	case sorted:
+	case reversed:
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	case insertion:
+		NewInsertionSortFunc(data, lt).InsertionSort()
+	case tim:
+		NewTimSortFunc(data, lt).TimSort()
+	case intro:
+		NewIntroSortFunc(data, lt).IntroSort()
+	case parallel:
+		NewParallelSortFunc(data, lt).ParallelSort()
+	}
+}
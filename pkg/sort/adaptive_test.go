@@ -0,0 +1,113 @@
+package sort
+
+import (
+	"ProjectGoLive/pkg/models"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func Test_choose(t *testing.T) {
+	ints := func(n int) []int {
+		d := make([]int, n)
+		for i := range d {
+			d[i] = i
+		}
+		return d
+	}
+	shuffled := func(n int) []int {
+		d := ints(n)
+		rand.Shuffle(len(d), func(i, j int) { d[i], d[j] = d[j], d[i] })
+		return d
+	}
+	lt := Ascending(func(i int) int { return i })
+
+	nearly := ints(1000)
+	nearly[100], nearly[500] = nearly[500], nearly[100]
+	reverse := ints(1000)
+	NewInsertionSortFunc(reverse, lt.Reverse()).InsertionSort()
+
+	tests := []struct {
+		name   string
+		data   []int
+		stable bool
+		want   algorithm
+	}{
+		{name: "Tiny", data: shuffled(10), want: insertion},
+		{name: "Sorted", data: ints(1000), want: sorted},
+		{name: "Reversed", data: reverse, want: reversed},
+		{name: "Nearly sorted", data: nearly, want: tim},
+		{name: "Shuffled", data: shuffled(1000), want: intro},
+		{name: "Shuffled and stable", data: shuffled(1000), stable: true, want: tim},
+		{name: "Long", data: shuffled(parallelThreshold), want: parallel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := choose(tt.data, lt, tt.stable); got != tt.want {
+				t.Errorf("choose() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Sort(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	// create a long list of products, with ties
+	long := []*models.Product{}
+	for len(long) < parallelThreshold {
+		long = append(long, list2...)
+	}
+
+	inputs := map[string]func(spec Spec) []*models.Product{
+		"Tiny": func(Spec) []*models.Product {
+			return append([]*models.Product{}, list2[:8]...)
+		},
+		"Shuffled": func(Spec) []*models.Product {
+			d := append([]*models.Product{}, list2...)
+			rand.Shuffle(len(d), func(i, j int) { d[i], d[j] = d[j], d[i] })
+			return d
+		},
+		"Sorted": func(spec Spec) []*models.Product {
+			d := append([]*models.Product{}, list2...)
+			NewMergeSortFunc(d, spec.Less()).MergeSort()
+			return d
+		},
+		"Reversed": func(spec Spec) []*models.Product {
+			d := append([]*models.Product{}, list2...)
+			NewMergeSortFunc(d, spec.Less().Reverse()).MergeSort()
+			return d
+		},
+		"Nearly sorted": func(spec Spec) []*models.Product {
+			d := append([]*models.Product{}, long...)
+			NewMergeSortFunc(d, spec.Less()).MergeSort()
+			d[10], d[len(d)-10] = d[len(d)-10], d[10]
+			return d
+		},
+		"Long": func(Spec) []*models.Product {
+			d := append([]*models.Product{}, long...)
+			rand.Shuffle(len(d), func(i, j int) { d[i], d[j] = d[j], d[i] })
+			return d
+		},
+	}
+
+	for mode, spec := range Presets {
+		for name, input := range inputs {
+			t.Run(mode+"/"+name, func(t *testing.T) {
+				got := input(spec)
+
+				// a stable merge sort of the same input is the reference
+				want := append([]*models.Product{}, got...)
+				NewMergeSortFunc(want, spec.Less()).MergeSort()
+
+				Sort(got, spec)
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("sort didn't sort: got %v, want %v at index %v", got[i].ProductID, want[i].ProductID, i)
+					}
+				}
+			})
+		}
+	}
+}
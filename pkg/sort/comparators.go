@@ -0,0 +1,83 @@
+package sort
+
+import (
+	"time"
+
+	"ProjectGoLive/pkg/models"
+	"ProjectGoLive/pkg/promo"
+)
+
+// comparators for orders
+var (
+	// OrdersByNewest sorts orders from the latest
+	// placed to the earliest.
+	OrdersByNewest = Chain(
+		Descending(func(o *models.Orders) int64 { return o.Created.UnixNano() }),
+		Descending(func(o *models.Orders) int { return o.OrderID }),
+	)
+
+	// OrdersByStatus sorts orders in the order of
+	// models.Status, so pending orders come first,
+	// then from the latest placed to the earliest.
+	OrdersByStatus = Chain(
+		Ascending(func(o *models.Orders) int { return o.Status }),
+		OrdersByNewest,
+	)
+
+	// OrdersByProduct sorts orders by the name of the
+	// product ordered, then from the latest placed to
+	// the earliest.
+	OrdersByProduct = Chain(
+		Ascending(func(o *models.Orders) string { return o.Product.Name }),
+		OrdersByNewest,
+	)
+
+	// OrdersByBuyer sorts orders by the user who placed
+	// them, then from the latest placed to the earliest.
+	OrdersByBuyer = Chain(
+		Ascending(func(o *models.Orders) string { return o.UserID }),
+		OrdersByNewest,
+	)
+)
+
+// comparators for shopping cart items
+var (
+	// CartItemsByName sorts items by the name
+	// of the product, then of the variant.
+	CartItemsByName = Chain(
+		Ascending(func(c *models.CartItem) string { return c.Product.Name }),
+		Ascending(func(c *models.CartItem) string { return c.Product.ProductID }),
+		Ascending(func(c *models.CartItem) string { return c.Product.Variant }),
+		Ascending(func(c *models.CartItem) int { return c.Product.VariantID }),
+	)
+
+	// CartItemsBySeller groups items by the seller
+	// of the product, then sorts them by name.
+	CartItemsBySeller = Chain(
+		Ascending(func(c *models.CartItem) string { return c.Product.SellerID }),
+		CartItemsByName,
+	)
+
+	// CartItemsByRecent sorts items from the latest
+	// added or changed to the earliest.
+	CartItemsByRecent = Chain(
+		Descending(func(c *models.CartItem) int64 { return c.Modified.UnixNano() }),
+		CartItemsByName,
+	)
+)
+
+// CartItemsBySubtotal returns a Less which sorts items
+// from the highest price for the quantity to the lowest,
+// at their prices at the time now.
+func CartItemsBySubtotal(now time.Time) Less[*models.CartItem] {
+	return Chain(
+		Descending(func(c *models.CartItem) float64 { return cartItemSubtotal(c, now) }),
+		CartItemsByName,
+	)
+}
+
+// cartItemSubtotal returns the price of the item for the
+// quantity in the cart, after its discount or promotion.
+func cartItemSubtotal(c *models.CartItem, now time.Time) float64 {
+	return promo.Exact(c.Product.Price, c.Product.DiscountID, c.Product.Promotion, now) * float64(c.Qty)
+}
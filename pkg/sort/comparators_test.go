@@ -0,0 +1,107 @@
+package sort
+
+import (
+	"ProjectGoLive/pkg/models"
+	"testing"
+	"time"
+)
+
+func Test_OrdersComparators(t *testing.T) {
+	now := time.Now()
+	orders := func() []*models.Orders {
+		o := []*models.Orders{
+			{OrderID: 1, UserID: "bob", Status: 1, Created: now.Add(-2 * time.Hour)},
+			{OrderID: 2, UserID: "amy", Status: 0, Created: now.Add(-time.Hour)},
+			{OrderID: 3, UserID: "bob", Status: 0, Created: now.Add(-3 * time.Hour)},
+			{OrderID: 4, UserID: "amy", Status: 2, Created: now.Add(-time.Hour)},
+		}
+		o[0].Product.Name = "Kaya"
+		o[1].Product.Name = "Durian"
+		o[2].Product.Name = "Kaya"
+		o[3].Product.Name = "Bread"
+		return o
+	}
+
+	tests := []struct {
+		name string
+		lt   Less[*models.Orders]
+		want []int
+	}{
+		{name: "Newest", lt: OrdersByNewest, want: []int{4, 2, 1, 3}},
+		{name: "Status", lt: OrdersByStatus, want: []int{2, 3, 1, 4}},
+		{name: "Product", lt: OrdersByProduct, want: []int{4, 2, 1, 3}},
+		{name: "Buyer", lt: OrdersByBuyer, want: []int{4, 2, 1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := orders()
+			NewMergeSortFunc(o, tt.lt).MergeSort()
+			for i, id := range tt.want {
+				if o[i].OrderID != id {
+					t.Errorf("sort didn't sort: got %v, want %v at index %v", o[i].OrderID, id, i)
+				}
+			}
+		})
+	}
+}
+
+func Test_CartItemsComparators(t *testing.T) {
+	items := func() []*models.CartItem {
+		c := make([]*models.CartItem, 3)
+		for i := range c {
+			c[i] = &models.CartItem{}
+		}
+		c[0].Product.ProductID, c[0].Product.Name, c[0].Product.SellerID = "1", "Milk", "s2"
+		c[0].Product.Price, c[0].Qty = 3.00, 2
+		c[1].Product.ProductID, c[1].Product.Name, c[1].Product.SellerID = "2", "Bread", "s2"
+		c[1].Product.Price, c[1].Qty, c[1].Product.DiscountID = 5.00, 1, 5
+		c[2].Product.ProductID, c[2].Product.Name, c[2].Product.SellerID = "3", "Kaya", "s1"
+		c[2].Product.Price, c[2].Qty = 4.50, 1
+		return c
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		lt   Less[*models.CartItem]
+		sale bool
+		want []string
+	}{
+		{name: "Name", lt: CartItemsByName, want: []string{"2", "3", "1"}},
+		{name: "Seller", lt: CartItemsBySeller, want: []string{"3", "2", "1"}},
+		{name: "Subtotal", lt: CartItemsBySubtotal(now), want: []string{"1", "3", "2"}},
+		{name: "Subtotal on sale", lt: CartItemsBySubtotal(now), sale: true, want: []string{"3", "2", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := items()
+			if tt.sale {
+				c[0].Product.Promotion = &models.Promotion{Kind: models.PromotionPercent, Value: 50, Starts: now.Add(-time.Hour), Ends: now.Add(time.Hour)}
+			}
+			NewInsertionSortFunc(c, tt.lt).InsertionSort()
+			for i, id := range tt.want {
+				if c[i].Product.ProductID != id {
+					t.Errorf("sort didn't sort: got %v, want %v at index %v", c[i].Product.ProductID, id, i)
+				}
+			}
+		})
+	}
+}
+
+func Test_CartItemsByNameVariants(t *testing.T) {
+	c := make([]*models.CartItem, 3)
+	for i, variant := range []string{"Pack of 6", "1kg", "500g"} {
+		c[i] = &models.CartItem{}
+		c[i].Product.ProductID, c[i].Product.Name = "4", "Chicken Thigh"
+		c[i].Product.VariantID, c[i].Product.Variant = i+1, variant
+	}
+
+	NewInsertionSortFunc(c, CartItemsByName).InsertionSort()
+	for i, want := range []string{"1kg", "500g", "Pack of 6"} {
+		if c[i].Product.Variant != want {
+			t.Errorf("sort didn't sort: got %v, want %v at index %v", c[i].Product.Variant, want, i)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package sort
+
+import (
+	"ProjectGoLive/pkg/models"
+	"fmt"
+	"math"
+	"math/rand"
+	stdsort "sort"
+	"testing"
+	"time"
+)
+
+// algorithms are run over random products and checked
+// against a reference sort. Stable algorithms must also
+// keep products which sort equal in their order.
+var algorithms = []struct {
+	name   string
+	stable bool
+	sort   func(data []*models.Product, lt Less[*models.Product])
+}{
+	{name: "InsertionSort", stable: true, sort: func(d []*models.Product, lt Less[*models.Product]) {
+		NewInsertionSortFunc(d, lt).InsertionSort()
+	}},
+	{name: "IntroSort", sort: func(d []*models.Product, lt Less[*models.Product]) {
+		NewIntroSortFunc(d, lt).IntroSort()
+	}},
+	{name: "IntroSort heap sort", sort: func(d []*models.Product, lt Less[*models.Product]) {
+		// start with no depth left so it falls back to heap sort
+		NewIntroSortFunc(d, lt).introSortUtil(0, len(d)-1)
+	}},
+	{name: "MergeSort", stable: true, sort: func(d []*models.Product, lt Less[*models.Product]) {
+		NewMergeSortFunc(d, lt).MergeSort()
+	}},
+	{name: "QuickSort", sort: func(d []*models.Product, lt Less[*models.Product]) {
+		NewQuickSortFunc(d, lt).QuickSort(0, len(d)-1)
+	}},
+	{name: "TimSort", stable: true, sort: func(d []*models.Product, lt Less[*models.Product]) {
+		NewTimSortFunc(d, lt).TimSort()
+	}},
+	{name: "ParallelSort", stable: true, sort: func(d []*models.Product, lt Less[*models.Product]) {
+		// split the products between more workers than
+		// there may be CPUs, whatever their number
+		ps := NewParallelSortFunc(d, lt)
+		ps.workers = 3
+		ps.parallelSort()
+	}},
+	{name: "TopK", sort: func(d []*models.Product, lt Less[*models.Product]) {
+		copy(d, TopK(d, len(d), lt))
+	}},
+	{name: "SortFunc", sort: SortFunc[*models.Product]},
+	{name: "SortStableFunc", stable: true, sort: SortStableFunc[*models.Product]},
+}
+
+// fuzzSpecs are the specs the products are sorted by.
+var fuzzSpecs = []string{
+	"sold:desc,price,rating:desc,ratings:desc,stock:desc",
+	"rating:desc,ratings:desc,price,sold:desc,stock:desc",
+	"price",
+	"rating:desc",
+	"created:desc,name",
+	"value:desc,discount:desc,instock:desc",
+}
+
+// fuzzProducts makes a product from every 4 bytes of b. The
+// fields take few values, so there are many ties, and some
+// of the prices and ratings are NaN.
+func fuzzProducts(b []byte) []*models.Product {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	products := []*models.Product{}
+	for i := 0; i+4 <= len(b); i += 4 {
+		p := &models.Product{
+			ProductID:  len(products) + 1,
+			Name:       string(rune('a' + b[i]%3)),
+			Price:      float64(b[i]%4) + 0.5,
+			Rating:     float64(b[i+1] % 6),
+			RatingNum:  int(b[i+1] / 64),
+			UnitSold:   int(b[i+2] % 3),
+			DiscountID: int(b[i+2] / 43),
+			Inventory:  int(b[i+3] % 2),
+			Created:    start.Add(time.Duration(b[i+3]%4) * time.Hour),
+		}
+		if b[i] == 255 {
+			p.Price = math.NaN()
+		}
+		if b[i+1]%16 == 15 {
+			p.Rating = math.NaN()
+		}
+		products = append(products, p)
+	}
+	return products
+}
+
+// checkAlgorithms sorts the products with every algorithm
+// and spec and compares them with a stable reference sort.
+func checkAlgorithms(t *testing.T, products []*models.Product) {
+	for _, text := range fuzzSpecs {
+		lt := MustParseSpec(text).Less()
+
+		want := append([]*models.Product{}, products...)
+		stdsort.SliceStable(want, func(i, j int) bool { return lt(want[i], want[j]) })
+
+		for _, a := range algorithms {
+			got := append([]*models.Product{}, products...)
+			a.sort(got, lt)
+
+			for i := range want {
+				if a.stable && got[i] != want[i] {
+					t.Fatalf("%s by %q is not stable: got %v, want %v at index %v", a.name, text, got[i].ProductID, want[i].ProductID, i)
+				}
+				if lt(got[i], want[i]) || lt(want[i], got[i]) {
+					t.Fatalf("%s by %q didn't sort: got %v, want %v at index %v", a.name, text, got[i].ProductID, want[i].ProductID, i)
+				}
+			}
+		}
+	}
+}
+
+func Test_SortProperties(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	for _, n := range []int{0, 1, 2, 3, 16, 17, 33, 100, 257} {
+		t.Run(fmt.Sprintf("%d products", n), func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				b := make([]byte, 4*n)
+				r.Read(b)
+				products := fuzzProducts(b)
+				checkAlgorithms(t, products)
+				if t.Failed() {
+					t.Logf("seed %v", seed)
+					return
+				}
+			}
+		})
+	}
+}
+
+func Test_SortPropertiesSorted(t *testing.T) {
+	b := make([]byte, 4*100)
+	rand.Read(b)
+	products := fuzzProducts(b)
+
+	// already sorted and reversed products are
+	// handled separately by Sort
+	for _, text := range fuzzSpecs {
+		lt := MustParseSpec(text).Less()
+		sorted := append([]*models.Product{}, products...)
+		stdsort.SliceStable(sorted, func(i, j int) bool { return lt(sorted[i], sorted[j]) })
+		checkAlgorithms(t, sorted)
+
+		reversed := append([]*models.Product{}, sorted...)
+		for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+			reversed[i], reversed[j] = reversed[j], reversed[i]
+		}
+		checkAlgorithms(t, reversed)
+	}
+}
+
+func Test_lessNaN(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		x, y float64
+		want bool
+	}{
+		{x: 1, y: 2, want: true},
+		{x: 2, y: 1, want: false},
+		{x: nan, y: 1, want: true},
+		{x: 1, y: nan, want: false},
+		{x: nan, y: nan, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := less(tt.x, tt.y); got != tt.want {
+			t.Errorf("less(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func FuzzSort(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1, 2, 3, 4})
+	f.Add([]byte{255, 15, 0, 0, 255, 31, 0, 0, 1, 15, 1, 1})
+	f.Add([]byte("equal prices, equal prices, equal prices, equal prices"))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		checkAlgorithms(t, fuzzProducts(b))
+	})
+}
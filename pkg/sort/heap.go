@@ -0,0 +1,46 @@
+package sort
+
+// heap is a binary heap with the element sorted last by lt
+// at the top. It is used by TopK to keep the best elements
+// seen, and by IntroSort to fall back to heap sort.
+type heap[T any] struct {
+	arr []T
+	lt  Less[T]
+}
+
+// heapify arranges the elements into a heap.
+func (h *heap[T]) heapify() {
+	n := len(h.arr)
+	for i := n/2 - 1; i >= 0; i-- {
+		h.down(i, n)
+	}
+}
+
+// sortDown moves the top to the end of the heap until it
+// is empty, leaving the elements in the order of lt.
+func (h *heap[T]) sortDown() {
+	for n := len(h.arr) - 1; n > 0; n-- {
+		h.arr[0], h.arr[n] = h.arr[n], h.arr[0]
+		h.down(0, n)
+	}
+}
+
+// down moves the element at i down the first n elements
+// of the heap until neither of its children is sorted
+// after it.
+func (h *heap[T]) down(i, n int) {
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && h.lt(h.arr[child], h.arr[child+1]) {
+			child++
+		}
+		if !h.lt(h.arr[i], h.arr[child]) {
+			return
+		}
+		h.arr[i], h.arr[child] = h.arr[child], h.arr[i]
+		i = child
+	}
+}
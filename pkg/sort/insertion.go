@@ -2,31 +2,27 @@ package sort
 
 import "ProjectGoLive/pkg/models"
 
-type InsertionSort struct {
-	arr []*models.Product
-	lt  func(*models.Product, *models.Product) bool
+type InsertionSort[T any] struct {
+	arr []T
+	lt  Less[T]
 }
 
-func NewInsertionSort(data []*models.Product, sortBy int) *InsertionSort {
-	var sortLogic func(*models.Product, *models.Product) bool
-	switch sortBy {
-	case 0:
-		sortLogic = sortByPop
-	case 1:
-		sortLogic = sortByRatings
-	case 2:
-		sortLogic = sortByPriceA
-	case 3:
-		sortLogic = sortByPriceD
-	}
+// NewInsertionSort returns an InsertionSort which sorts
+// the products by one of the modes in models.SortBy.
+func NewInsertionSort(data []*models.Product, sortBy int) *InsertionSort[*models.Product] {
+	return NewInsertionSortFunc(data, ProductLess(sortBy))
+}
 
-	return &InsertionSort{
+// NewInsertionSortFunc returns an InsertionSort which
+// sorts the data with lt.
+func NewInsertionSortFunc[T any](data []T, lt Less[T]) *InsertionSort[T] {
+	return &InsertionSort[T]{
 		arr: data,
-		lt:  sortLogic,
+		lt:  lt,
 	}
 }
 
-func (s InsertionSort) InsertionSort() {
+func (s InsertionSort[T]) InsertionSort() {
 	for i := 1; i < len(s.arr); i++ {
 		key := s.arr[i]
 		j := i - 1
@@ -2,43 +2,43 @@ package sort
 
 import (
 	"ProjectGoLive/pkg/models"
-	"math"
+	"math/bits"
 )
 
-type IntroSort struct {
-	arr        []*models.Product
-	lt         func(*models.Product, *models.Product) bool
+type IntroSort[T any] struct {
+	arr        []T
+	lt         Less[T]
 	depthLimit int
 }
 
-func NewIntroSort(data []*models.Product, sortBy int) *IntroSort {
-	var sortLogic func(*models.Product, *models.Product) bool
-	switch sortBy {
-	case 0:
-		sortLogic = sortByPop
-	case 1:
-		sortLogic = sortByRatings
-	case 2:
-		sortLogic = sortByPriceA
-	case 3:
-		sortLogic = sortByPriceD
-	}
+// NewIntroSort returns an IntroSort which sorts the
+// products by one of the modes in models.SortBy.
+func NewIntroSort(data []*models.Product, sortBy int) *IntroSort[*models.Product] {
+	return NewIntroSortFunc(data, ProductLess(sortBy))
+}
 
-	return &IntroSort{
+// NewIntroSortFunc returns an IntroSort which sorts
+// the data with lt.
+func NewIntroSortFunc[T any](data []T, lt Less[T]) *IntroSort[T] {
+	return &IntroSort[T]{
 		arr: data,
-		lt:  sortLogic,
+		lt:  lt,
 	}
 }
 
-func (s IntroSort) IntroSort() {
+func (s IntroSort[T]) IntroSort() {
 	begin := 0
 	end := len(s.arr) - 1
 
-	s.depthLimit = 2 * int(math.Round(math.Log2(float64(end))))
+	// allow 2*log2(n) levels of quick sort before falling
+	// back to heap sort. bits.Len is used rather than
+	// math.Log2, which is -Inf or NaN for fewer than 2
+	// elements.
+	s.depthLimit = 2 * bits.Len(uint(len(s.arr)))
 	s.introSortUtil(begin, end)
 }
 
-func (s IntroSort) introSortUtil(begin, end int) {
+func (s IntroSort[T]) introSortUtil(begin, end int) {
 	size := end - begin
 	if size <= 16 {
 		s.insertionSort(begin, end)
@@ -61,7 +61,7 @@ func (s IntroSort) introSortUtil(begin, end int) {
 	s.introSortUtil(pivotIdx+1, end)
 }
 
-func (s IntroSort) partition(low, high int) int {
+func (s IntroSort[T]) partition(low, high int) int {
 	pivot := s.arr[high]
 	i := low - 1
 	for j := low; j < high; j++ {
@@ -74,7 +74,7 @@ func (s IntroSort) partition(low, high int) int {
 	return i + 1
 }
 
-func (s IntroSort) medianOfThree(a, b, c int) int {
+func (s IntroSort[T]) medianOfThree(a, b, c int) int {
 	da := s.arr[a]
 	db := s.arr[b]
 	dc := s.arr[c]
@@ -105,7 +105,7 @@ func (s IntroSort) medianOfThree(a, b, c int) int {
 	return a
 }
 
-func (s IntroSort) insertionSort(begin, end int) {
+func (s IntroSort[T]) insertionSort(begin, end int) {
 	left := begin
 
 	for i := left + 1; i <= end; i++ {
@@ -119,37 +119,8 @@ func (s IntroSort) insertionSort(begin, end int) {
 	}
 }
 
-func (s IntroSort) maxHeap(i, n, begin int) {
-	temp := s.arr[begin+i-1]
-	child := 0
-
-	for i <= n/2 {
-		child = 2 * i
-		if child < n && s.lt(s.arr[begin+child-1], s.arr[begin+child]) {
-			child++
-		}
-
-		if s.lt(s.arr[begin+child-1], temp) {
-			break
-		}
-
-		s.arr[begin+i-1] = s.arr[begin+child-1]
-		i = child
-	}
-	s.arr[begin+i-1] = temp
-}
-
-func (s IntroSort) heapify(begin, end, n int) {
-	for i := n / 2; i >= 1; i-- {
-		s.maxHeap(i, n, begin)
-	}
-}
-
-func (s IntroSort) heapSort(begin, end int) {
-	n := end - begin
-	s.heapify(begin, end, n)
-	for i := n; i >= 1; i-- {
-		s.arr[begin], s.arr[begin+1] = s.arr[begin+1], s.arr[begin]
-		s.maxHeap(1, i, begin)
-	}
+func (s IntroSort[T]) heapSort(begin, end int) {
+	h := &heap[T]{arr: s.arr[begin : end+1], lt: s.lt}
+	h.heapify()
+	h.sortDown()
 }
@@ -0,0 +1,71 @@
+package sort
+
+// Less reports whether a should be sorted before b. The
+// algorithms in this package take a Less to sort any
+// slice, and the functions below build one from the
+// fields of the elements.
+type Less[T any] func(a, b T) bool
+
+// Ordered is the set of types which can be compared
+// with the < operator.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Ascending returns a Less which sorts elements by the
+// key from lowest to highest. A NaN key is lower than
+// any other key.
+func Ascending[T any, K Ordered](key func(T) K) Less[T] {
+	return func(a, b T) bool {
+		return less(key(a), key(b))
+	}
+}
+
+// Descending returns a Less which sorts elements by the
+// key from highest to lowest. A NaN key is lower than
+// any other key.
+func Descending[T any, K Ordered](key func(T) K) Less[T] {
+	return func(a, b T) bool {
+		return less(key(b), key(a))
+	}
+}
+
+// less reports whether x is lower than y. Unlike the <
+// operator, it orders NaN below every other value, so the
+// comparison stays consistent when a key is NaN, such as
+// the rating of a product which has not been rated.
+func less[K Ordered](x, y K) bool {
+	return x < y || (isNaN(x) && !isNaN(y))
+}
+
+// isNaN reports whether x is a floating point NaN,
+// the only value which is not equal to itself.
+func isNaN[K Ordered](x K) bool {
+	return x != x
+}
+
+// Chain returns a Less which sorts elements by the first
+// of the lesses, breaking ties with the ones after it.
+func Chain[T any](lesses ...Less[T]) Less[T] {
+	return func(a, b T) bool {
+		for _, lt := range lesses {
+			if lt(a, b) {
+				return true
+			}
+			if lt(b, a) {
+				return false
+			}
+		}
+		return false
+	}
+}
+
+// Reverse returns a Less which sorts elements in the
+// opposite order of lt.
+func (lt Less[T]) Reverse() Less[T] {
+	return func(a, b T) bool {
+		return lt(b, a)
+	}
+}
@@ -0,0 +1,68 @@
+package sort
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func Test_Chain(t *testing.T) {
+	type pair struct {
+		a int
+		b string
+	}
+	lt := Chain(
+		Ascending(func(p pair) int { return p.a }),
+		Descending(func(p pair) string { return p.b }),
+	)
+
+	tests := []struct {
+		name string
+		p1   pair
+		p2   pair
+		want bool
+	}{
+		{name: "First key is lower", p1: pair{1, "a"}, p2: pair{2, "b"}, want: true},
+		{name: "First key is higher", p1: pair{2, "b"}, p2: pair{1, "a"}, want: false},
+		{name: "Tie broken by second key", p1: pair{1, "b"}, p2: pair{1, "a"}, want: true},
+		{name: "Equal", p1: pair{1, "a"}, p2: pair{1, "a"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lt(tt.p1, tt.p2); got != tt.want {
+				t.Errorf("lt(%v, %v) = %v, want %v", tt.p1, tt.p2, got, tt.want)
+			}
+			if got := lt.Reverse()(tt.p2, tt.p1); got != tt.want {
+				t.Errorf("Reverse()(%v, %v) = %v, want %v", tt.p2, tt.p1, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NewSortFunc(t *testing.T) {
+	want := make([]int, 100)
+	for i := range want {
+		want[i] = i / 2
+	}
+	lt := Ascending(func(i int) int { return i })
+
+	sorts := map[string]func([]int){
+		"InsertionSort": func(d []int) { NewInsertionSortFunc(d, lt).InsertionSort() },
+		"IntroSort":     func(d []int) { NewIntroSortFunc(d, lt).IntroSort() },
+		"MergeSort":     func(d []int) { NewMergeSortFunc(d, lt).MergeSort() },
+		"QuickSort":     func(d []int) { NewQuickSortFunc(d, lt).QuickSort(0, len(d)-1) },
+		"TimSort":       func(d []int) { NewTimSortFunc(d, lt).TimSort() },
+	}
+
+	for name, sort := range sorts {
+		t.Run(name, func(t *testing.T) {
+			got := append([]int{}, want...)
+			rand.Shuffle(len(got), func(i, j int) { got[i], got[j] = got[j], got[i] })
+			sort(got)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("sort didn't sort: got %v", got)
+			}
+		})
+	}
+}
@@ -2,31 +2,27 @@ package sort
 
 import "ProjectGoLive/pkg/models"
 
-type MergeSort struct {
-	arr []*models.Product
-	lt  func(*models.Product, *models.Product) bool
+type MergeSort[T any] struct {
+	arr []T
+	lt  Less[T]
 }
 
-func NewMergeSort(data []*models.Product, sortBy int) *MergeSort {
-	var sortLogic func(*models.Product, *models.Product) bool
-	switch sortBy {
-	case 0:
-		sortLogic = sortByPop
-	case 1:
-		sortLogic = sortByRatings
-	case 2:
-		sortLogic = sortByPriceA
-	case 3:
-		sortLogic = sortByPriceD
-	}
+// NewMergeSort returns a MergeSort which sorts the
+// products by one of the modes in models.SortBy.
+func NewMergeSort(data []*models.Product, sortBy int) *MergeSort[*models.Product] {
+	return NewMergeSortFunc(data, ProductLess(sortBy))
+}
 
-	return &MergeSort{
+// NewMergeSortFunc returns a MergeSort which sorts
+// the data with lt.
+func NewMergeSortFunc[T any](data []T, lt Less[T]) *MergeSort[T] {
+	return &MergeSort[T]{
 		arr: data,
-		lt:  sortLogic,
+		lt:  lt,
 	}
 }
 
-func (s *MergeSort) MergeSort() {
+func (s *MergeSort[T]) MergeSort() {
 	n := len(s.arr)
 	for size := 1; size < n; size *= 2 {
 		for left := 0; left < n; left += 2 * size {
@@ -43,11 +39,11 @@ func (s *MergeSort) MergeSort() {
 	}
 }
 
-func (s *MergeSort) merge(l, m, r int) {
+func (s *MergeSort[T]) merge(l, m, r int) {
 	len1 := m - l + 1
 	len2 := r - m
-	left := make([]*models.Product, len1)
-	right := make([]*models.Product, len2)
+	left := make([]T, len1)
+	right := make([]T, len2)
 
 	for i := 0; i < len1; i++ {
 		left[i] = s.arr[l+i]
@@ -61,7 +57,8 @@ func (s *MergeSort) merge(l, m, r int) {
 	k := l
 
 	for i < len1 && j < len2 {
-		if s.lt(left[i], right[j]) {
+		// take from the left on ties to keep the sort stable
+		if !s.lt(right[j], left[i]) {
 			s.arr[k] = left[i]
 			i++
 		} else {
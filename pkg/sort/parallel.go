@@ -0,0 +1,112 @@
+package sort
+
+import (
+	"runtime"
+	"sync"
+
+	"ProjectGoLive/pkg/models"
+)
+
+// parallelThreshold is the length below which ParallelSort
+// sorts on a single goroutine, since starting the workers
+// and merging their runs costs more than it saves. See
+// Benchmark_ParallelSort for the crossover point.
+const parallelThreshold = 4096
+
+// ParallelSort is a merge sort which splits the data into
+// one run for each of GOMAXPROCS workers, sorts the runs
+// concurrently with TimSort, then merges pairs of runs
+// concurrently until one is left. ParallelSort is stable.
+type ParallelSort[T any] struct {
+	arr     []T
+	lt      Less[T]
+	workers int
+}
+
+// NewParallelSort returns a ParallelSort which sorts the
+// products by one of the modes in models.SortBy.
+func NewParallelSort(data []*models.Product, sortBy int) *ParallelSort[*models.Product] {
+	return NewParallelSortFunc(data, ProductLess(sortBy))
+}
+
+// NewParallelSortFunc returns a ParallelSort which sorts
+// the data with lt.
+func NewParallelSortFunc[T any](data []T, lt Less[T]) *ParallelSort[T] {
+	return &ParallelSort[T]{
+		arr:     data,
+		lt:      lt,
+		workers: runtime.GOMAXPROCS(0),
+	}
+}
+
+func (s *ParallelSort[T]) ParallelSort() {
+	if len(s.arr) < parallelThreshold || s.workers < 2 {
+		NewTimSortFunc(s.arr, s.lt).TimSort()
+		return
+	}
+	s.parallelSort()
+}
+
+// parallelSort sorts the data with the workers whatever
+// its length.
+func (s *ParallelSort[T]) parallelSort() {
+	n := len(s.arr)
+
+	// bounds holds the start of every run, followed
+	// by the end of the data
+	size := (n + s.workers - 1) / s.workers
+	bounds := []int{}
+	for i := 0; i < n; i += size {
+		bounds = append(bounds, i)
+	}
+	bounds = append(bounds, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < len(bounds)-1; i++ {
+		wg.Add(1)
+		go func(l, r int) {
+			defer wg.Done()
+			NewTimSortFunc(s.arr[l:r], s.lt).TimSort()
+		}(bounds[i], bounds[i+1])
+	}
+	wg.Wait()
+
+	buf := make([]T, n)
+	for len(bounds) > 2 {
+		merged := []int{}
+		for i := 0; i < len(bounds)-1; i += 2 {
+			merged = append(merged, bounds[i])
+			if i+2 >= len(bounds) {
+				// an odd run out is merged in the next round
+				continue
+			}
+			wg.Add(1)
+			go func(l, m, r int) {
+				defer wg.Done()
+				s.merge(buf, l, m, r)
+			}(bounds[i], bounds[i+1], bounds[i+2])
+		}
+		wg.Wait()
+		bounds = append(merged, n)
+	}
+}
+
+// merge merges the sorted runs arr[l:m] and arr[m:r],
+// using the same part of buf to hold the result.
+func (s *ParallelSort[T]) merge(buf []T, l, m, r int) {
+	i, j, k := l, m, l
+	for i < m && j < r {
+		// take from the left run on ties to keep the sort stable
+		if s.lt(s.arr[j], s.arr[i]) {
+			buf[k] = s.arr[j]
+			j++
+		} else {
+			buf[k] = s.arr[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], s.arr[i:m])
+	copy(buf[k:], s.arr[j:r])
+	copy(s.arr[l:r], buf[l:r])
+}
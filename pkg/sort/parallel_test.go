@@ -0,0 +1,93 @@
+package sort
+
+import (
+	"ProjectGoLive/pkg/models"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func Test_ParallelSort(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+	rand.Shuffle(len(list2), func(i, j int) { list2[i], list2[j] = list2[j], list2[i] })
+
+	ps := NewParallelSort(list2, 2)
+	ps.ParallelSort()
+
+	for i := 0; i < len(list2); i++ {
+		if list1[i].ProductID != ps.arr[i].ProductID {
+			t.Errorf("sort didn't sort: got %v, want %v at index %v", list2[i].ProductID, list1[i].ProductID, i)
+		}
+	}
+}
+
+func Test_ParallelSortWorkers(t *testing.T) {
+	for _, workers := range []int{2, 3, 4, 7} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			// create a list long enough to be split between the workers
+			list := []*models.Product{}
+			for len(list) < 2*parallelThreshold+5 {
+				list = append(list, list2...)
+			}
+			rand.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+
+			want := append([]*models.Product{}, list...)
+			NewMergeSort(want, 2).MergeSort()
+
+			ps := NewParallelSort(list, 2)
+			ps.workers = workers
+			ps.ParallelSort()
+
+			for i := range want {
+				if ps.lt(want[i], list[i]) || ps.lt(list[i], want[i]) {
+					t.Fatalf("sort didn't sort: got %v, want %v at index %v", list[i].ProductID, want[i].ProductID, i)
+				}
+			}
+		})
+	}
+}
+
+// Benchmark_ParallelSort compares ParallelSort with TimSort,
+// which it falls back to below parallelThreshold, on lists
+// of increasing length to find where it starts to pay off.
+// Run it with -cpu to compare different numbers of workers.
+func Benchmark_ParallelSort(b *testing.B) {
+	for _, n := range []int{256, 1024, 4096, 16384, 65536} {
+		// create a list of n products
+		list := []*models.Product{}
+		for len(list) < n {
+			list = append(list, list2...)
+		}
+		list = list[:n]
+
+		b.Run(fmt.Sprintf("TimSort/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// shuffle the list
+				b.StopTimer()
+				rand.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+				ts := NewTimSort(list, 2)
+				b.StartTimer()
+
+				// perform the sorting
+				ts.TimSort()
+			}
+		})
+
+		b.Run(fmt.Sprintf("ParallelSort/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// shuffle the list
+				b.StopTimer()
+				rand.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+				ps := NewParallelSort(list, 2)
+				ps.workers = runtime.GOMAXPROCS(0)
+				b.StartTimer()
+
+				// perform the sorting, splitting the list
+				// whatever its length so the crossover can be seen
+				ps.parallelSort()
+			}
+		})
+	}
+}
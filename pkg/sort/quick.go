@@ -2,31 +2,27 @@ package sort
 
 import "ProjectGoLive/pkg/models"
 
-type QuickSort struct {
-	arr []*models.Product
-	lt  func(*models.Product, *models.Product) bool
+type QuickSort[T any] struct {
+	arr []T
+	lt  Less[T]
 }
 
-func NewQuickSort(data []*models.Product, sortBy int) *QuickSort {
-	var sortLogic func(*models.Product, *models.Product) bool
-	switch sortBy {
-	case 0:
-		sortLogic = sortByPop
-	case 1:
-		sortLogic = sortByRatings
-	case 2:
-		sortLogic = sortByPriceA
-	case 3:
-		sortLogic = sortByPriceD
-	}
+// NewQuickSort returns a QuickSort which sorts the
+// products by one of the modes in models.SortBy.
+func NewQuickSort(data []*models.Product, sortBy int) *QuickSort[*models.Product] {
+	return NewQuickSortFunc(data, ProductLess(sortBy))
+}
 
-	return &QuickSort{
+// NewQuickSortFunc returns a QuickSort which sorts
+// the data with lt.
+func NewQuickSortFunc[T any](data []T, lt Less[T]) *QuickSort[T] {
+	return &QuickSort[T]{
 		arr: data,
-		lt:  sortLogic,
+		lt:  lt,
 	}
 }
 
-func (s QuickSort) QuickSort(l, r int) {
+func (s QuickSort[T]) QuickSort(l, r int) {
 	if l < r {
 		pivotIdx := s.partition(l, r)
 		s.QuickSort(l, pivotIdx-1)
@@ -34,7 +30,7 @@ func (s QuickSort) QuickSort(l, r int) {
 	}
 }
 
-func (s QuickSort) partition(l, r int) int {
+func (s QuickSort[T]) partition(l, r int) int {
 	pivot := s.arr[r]
 	i := l - 1
 	for j := l; j <= r-1; j++ {
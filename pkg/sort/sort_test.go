@@ -6,6 +6,7 @@ import (
 )
 
 func Test_sortByPriceA(t *testing.T) {
+	sortByPriceA := ProductLess(2)
 	type args struct {
 		p1 *models.Product
 		p2 *models.Product
@@ -130,6 +131,7 @@ func Test_sortByPriceV(t *testing.T) {
 func Benchmark_sortByPriceA(b *testing.B) {
 	p1 := &models.Product{ProductID: 1, Name: "Product 1", Desc: "This is Product 1", Price: 1.00, Inventory: 100, Rating: 3.0, RatingNum: 80, UnitSold: 100}
 	p2 := &models.Product{ProductID: 2, Name: "Product 2", Desc: "This is Product 2", Price: 1.00, Inventory: 80, Rating: 3.0, RatingNum: 80, UnitSold: 100}
+	sortByPriceA := ProductLess(2)
 
 	for i := 0; i < b.N; i++ {
 		sortByPriceA(p1, p2)
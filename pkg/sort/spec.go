@@ -0,0 +1,185 @@
+package sort
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ProjectGoLive/pkg/models"
+	"ProjectGoLive/pkg/promo"
+)
+
+// SortKey is a field of a product to sort by, from
+// lowest to highest unless Desc is set.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// Spec is a list of keys to sort products by. Products
+// are sorted by the first key, with ties broken by the
+// keys after it.
+type Spec []SortKey
+
+// productFields maps the fields products can be sorted
+// by in a Spec to a Less sorting them from lowest to
+// highest.
+var productFields = map[string]Less[*models.Product]{
+	"id":       Ascending(func(p *models.Product) int { return p.ProductID }),
+	"name":     Ascending(func(p *models.Product) string { return strings.ToLower(p.Name) }),
+	"rating":   Ascending(func(p *models.Product) float64 { return p.Rating }),
+	"ratings":  Ascending(func(p *models.Product) int { return p.RatingNum }),
+	"sold":     Ascending(func(p *models.Product) int { return p.UnitSold }),
+	"stock":    Ascending(func(p *models.Product) int { return p.Inventory }),
+	"created":  Ascending(func(p *models.Product) int64 { return p.Created.UnixNano() }),
+	"modified": Ascending(func(p *models.Product) int64 { return p.Modified.UnixNano() }),
+	"instock":  Ascending(inStock),
+}
+
+// priceFields maps the fields which depend on the price
+// products sell at, under their promotions, to a Less
+// sorting them from lowest to highest at a given time.
+// The time is fixed for a whole sort, so a promotion
+// ending halfway through does not reorder the products.
+var priceFields = map[string]func(now time.Time) Less[*models.Product]{
+	"price": func(now time.Time) Less[*models.Product] {
+		return Ascending(func(p *models.Product) float64 { return price(p, now) })
+	},
+	"discount": func(now time.Time) Less[*models.Product] {
+		return Ascending(func(p *models.Product) float64 { return discount(p, now) })
+	},
+	"value": func(now time.Time) Less[*models.Product] {
+		return Ascending(func(p *models.Product) float64 { return value(p, now) })
+	},
+}
+
+// Presets maps the sort modes in models.SortBy to
+// their Spec.
+var Presets = map[string]Spec{
+	models.SortBy[0]: MustParseSpec("sold:desc,price,rating:desc,ratings:desc,stock:desc"),
+	models.SortBy[1]: MustParseSpec("rating:desc,ratings:desc,price,sold:desc,stock:desc"),
+	models.SortBy[2]: MustParseSpec("price,sold:desc,rating:desc,ratings:desc,stock:desc"),
+	models.SortBy[3]: MustParseSpec("price:desc,sold:desc,rating:desc,ratings:desc,stock:desc"),
+	models.SortBy[4]: MustParseSpec("created:desc,id:desc"),
+	models.SortBy[5]: MustParseSpec("modified:desc,id:desc"),
+	models.SortBy[6]: MustParseSpec("discount:desc,sold:desc,price,rating:desc"),
+	models.SortBy[7]: MustParseSpec("value:desc,rating:desc,ratings:desc,price"),
+	models.SortBy[8]: MustParseSpec("instock:desc,sold:desc,price,rating:desc"),
+}
+
+// ParseSpec parses a Spec written as comma separated
+// fields, each followed by :asc or :desc, such as
+//
+//	price:asc,rating:desc,created:desc
+//
+// A field without a direction is sorted in ascending
+// order. ParseSpec returns an error if a field cannot
+// be sorted by or is given more than once.
+func ParseSpec(text string) (Spec, error) {
+	spec := Spec{}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(text, ",") {
+		field, dir := strings.TrimSpace(part), ""
+		if i := strings.IndexByte(field, ':'); i >= 0 {
+			field, dir = strings.TrimSpace(field[:i]), strings.TrimSpace(field[i+1:])
+		}
+		field = strings.ToLower(field)
+
+		if _, ok := productFields[field]; !ok && priceFields[field] == nil {
+			return nil, fmt.Errorf("sort: cannot sort by %q", field)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("sort: %q is sorted by more than once", field)
+		}
+		seen[field] = true
+
+		key := SortKey{Field: field}
+		switch strings.ToLower(dir) {
+		case "", "asc":
+		case "desc":
+			key.Desc = true
+		default:
+			return nil, fmt.Errorf("sort: unknown direction %q for %q", dir, field)
+		}
+		spec = append(spec, key)
+	}
+	return spec, nil
+}
+
+// MustParseSpec is like ParseSpec but panics if the
+// Spec cannot be parsed. It is for Specs written in
+// the code, such as Presets.
+func MustParseSpec(text string) Spec {
+	spec, err := ParseSpec(text)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// String returns the Spec in the form read by ParseSpec.
+func (s Spec) String() string {
+	parts := make([]string, len(s))
+	for i, key := range s {
+		dir := "asc"
+		if key.Desc {
+			dir = "desc"
+		}
+		parts[i] = key.Field + ":" + dir
+	}
+	return strings.Join(parts, ",")
+}
+
+// price returns the price the product sells at, at now,
+// after its discount or promotion.
+func price(p *models.Product, now time.Time) float64 {
+	return promo.Exact(p.Price, p.DiscountID, p.Promotion, now)
+}
+
+// discount returns the fraction taken off the price of
+// the product, at now, by its discount or promotion.
+func discount(p *models.Product, now time.Time) float64 {
+	if p.Price <= 0 {
+		return 0
+	}
+	return 1 - price(p, now)/p.Price
+}
+
+// value returns the rating of the product for every dollar
+// of its price at now. A free product is infinitely good
+// value, unless its rating is 0, which makes its value
+// NaN and sorts it below every other product.
+func value(p *models.Product, now time.Time) float64 {
+	return p.Rating / price(p, now)
+}
+
+// inStock returns 1 if the product is in stock, or 0.
+func inStock(p *models.Product) int {
+	if p.Inventory > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Less returns a Less which sorts products by the Spec,
+// at the prices they sell at now.
+func (s Spec) Less() Less[*models.Product] {
+	return s.LessAt(time.Now())
+}
+
+// LessAt is like Less but takes the prices of the
+// products at the time now.
+func (s Spec) LessAt(now time.Time) Less[*models.Product] {
+	lesses := make([]Less[*models.Product], len(s))
+	for i, key := range s {
+		if at, ok := priceFields[key.Field]; ok {
+			lesses[i] = at(now)
+		} else {
+			lesses[i] = productFields[key.Field]
+		}
+		if key.Desc {
+			lesses[i] = lesses[i].Reverse()
+		}
+	}
+	return Chain(lesses...)
+}
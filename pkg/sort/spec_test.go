@@ -0,0 +1,106 @@
+package sort
+
+import (
+	"ProjectGoLive/pkg/models"
+	"testing"
+	"time"
+)
+
+func Test_ParseSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "Directions", text: "price:asc,rating:desc,created:desc", want: "price:asc,rating:desc,created:desc"},
+		{name: "Default direction", text: "price", want: "price:asc"},
+		{name: "Spaces and case", text: " Price : DESC , name ", want: "price:desc,name:asc"},
+		{name: "Unknown field", text: "price,password", wantErr: true},
+		{name: "Unknown direction", text: "price:up", wantErr: true},
+		{name: "Repeated field", text: "price:asc,price:desc", wantErr: true},
+		{name: "Empty field", text: "price,,rating", wantErr: true},
+		{name: "Empty", text: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSpec(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSpec(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseSpec(%q) = %q, want %q", tt.text, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_Spec_Less(t *testing.T) {
+	now := time.Now()
+	products := []*models.Product{
+		{ProductID: 1, Price: 2.00, Rating: 4.0, Created: now.Add(-3 * time.Hour)},
+		{ProductID: 2, Price: 1.00, Rating: 3.0, Created: now.Add(-2 * time.Hour)},
+		{ProductID: 3, Price: 2.00, Rating: 4.0, Created: now.Add(-time.Hour)},
+		{ProductID: 4, Price: 2.00, Rating: 5.0, Created: now},
+	}
+
+	s := NewMergeSortFunc(products, MustParseSpec("price:asc,rating:desc,created:desc").Less())
+	s.MergeSort()
+
+	want := []int{2, 4, 3, 1}
+	for i, id := range want {
+		if products[i].ProductID != id {
+			t.Errorf("sort didn't sort: got %v, want %v at index %v", products[i].ProductID, id, i)
+		}
+	}
+}
+
+func Test_Presets(t *testing.T) {
+	for _, name := range models.SortBy {
+		if _, ok := Presets[name]; !ok {
+			t.Errorf("sort mode %q has no preset", name)
+		}
+	}
+}
+
+func Test_PresetsModes(t *testing.T) {
+	now := time.Now()
+	products := []*models.Product{
+		{ProductID: 1, Price: 10.00, DiscountID: 0, Rating: 4.0, Inventory: 0, UnitSold: 50,
+			Created: now.Add(-3 * time.Hour), Modified: now},
+		{ProductID: 2, Price: 10.00, DiscountID: 5, Rating: 3.5, Inventory: 5, UnitSold: 10,
+			Created: now.Add(-2 * time.Hour), Modified: now.Add(-2 * time.Hour)},
+		{ProductID: 3, Price: 2.00, DiscountID: 1, Rating: 4.5, Inventory: 0, UnitSold: 5,
+			Created: now, Modified: now.Add(-time.Hour)},
+		{ProductID: 4, Price: 4.00, DiscountID: 2, Rating: 0, Inventory: 3, UnitSold: 20,
+			Created: now.Add(-time.Hour), Modified: now.Add(-3 * time.Hour)},
+	}
+
+	tests := []struct {
+		mode string
+		want []int
+	}{
+		{mode: "Newest", want: []int{3, 4, 2, 1}},
+		{mode: "Recently Updated", want: []int{1, 3, 2, 4}},
+		{mode: "Biggest Discount", want: []int{2, 4, 3, 1}},
+		{mode: "Best Value", want: []int{3, 2, 1, 4}},
+		{mode: "In Stock", want: []int{4, 2, 1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			spec, ok := Presets[tt.mode]
+			if !ok {
+				t.Fatalf("no preset for %q", tt.mode)
+			}
+			got := append([]*models.Product{}, products...)
+			Sort(got, spec)
+			for i, id := range tt.want {
+				if got[i].ProductID != id {
+					t.Errorf("sort didn't sort: got %v, want %v at index %v", got[i].ProductID, id, i)
+				}
+			}
+		})
+	}
+}
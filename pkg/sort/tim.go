@@ -4,35 +4,31 @@ import "ProjectGoLive/pkg/models"
 
 const run = 16
 
-type TimSort struct {
-	arr []*models.Product
-	lt  func(*models.Product, *models.Product) bool
+type TimSort[T any] struct {
+	arr []T
+	lt  Less[T]
 }
 
-func NewTimSort(data []*models.Product, sortBy int) *TimSort {
-	var sortLogic func(*models.Product, *models.Product) bool
-	switch sortBy {
-	case 0:
-		sortLogic = sortByPop
-	case 1:
-		sortLogic = sortByRatings
-	case 2:
-		sortLogic = sortByPriceA
-	case 3:
-		sortLogic = sortByPriceD
-	}
+// NewTimSort returns a TimSort which sorts the
+// products by one of the modes in models.SortBy.
+func NewTimSort(data []*models.Product, sortBy int) *TimSort[*models.Product] {
+	return NewTimSortFunc(data, ProductLess(sortBy))
+}
 
-	return &TimSort{
+// NewTimSortFunc returns a TimSort which sorts
+// the data with lt.
+func NewTimSortFunc[T any](data []T, lt Less[T]) *TimSort[T] {
+	return &TimSort[T]{
 		arr: data,
-		lt:  sortLogic,
+		lt:  lt,
 	}
 }
 
 // TimSort is a naive implementation of the TimSort
 // which combines insertion sort and merge sort but
 // does not implement further optimizations during
-// the merge phase.
-func (s *TimSort) TimSort() {
+// the merge phase. TimSort is stable.
+func (s *TimSort[T]) TimSort() {
 	n := len(s.arr)
 	for i := 0; i < n; i += run {
 		if (i + run - 1) < (n - 1) {
@@ -57,7 +53,7 @@ func (s *TimSort) TimSort() {
 	}
 }
 
-func (s *TimSort) insertionSort(l, r int) {
+func (s *TimSort[T]) insertionSort(l, r int) {
 	for i := l + 1; i <= r; i++ {
 		temp := s.arr[i]
 		j := i - 1
@@ -69,11 +65,11 @@ func (s *TimSort) insertionSort(l, r int) {
 	}
 }
 
-func (s *TimSort) merge(l, m, r int) {
+func (s *TimSort[T]) merge(l, m, r int) {
 	len1 := m - l + 1
 	len2 := r - m
-	left := make([]*models.Product, len1)
-	right := make([]*models.Product, len2)
+	left := make([]T, len1)
+	right := make([]T, len2)
 
 	for i := 0; i < len1; i++ {
 		left[i] = s.arr[l+i]
@@ -87,7 +83,8 @@ func (s *TimSort) merge(l, m, r int) {
 	k := l
 
 	for i < len1 && j < len2 {
-		if s.lt(left[i], right[j]) {
+		// take from the left on ties to keep the sort stable
+		if !s.lt(right[j], left[i]) {
 			s.arr[k] = left[i]
 			i++
 		} else {
@@ -0,0 +1,78 @@
+package sort
+
+import "ProjectGoLive/pkg/models"
+
+// TopKProducts returns the first k products in the order of
+// one of the modes in models.SortBy. See TopK.
+func TopKProducts(data []*models.Product, k, sortBy int) []*models.Product {
+	return TopK(data, k, ProductLess(sortBy))
+}
+
+// TopK returns the first k elements of the data in the order
+// of lt, without sorting the rest, for showing the first
+// pages of a long list. It keeps the best k elements seen in
+// a heap, so it takes O(n log k) comparisons rather than the
+// O(n log n) of a full sort. If k is more than half the data,
+// the data is sorted in full with SortStableFunc instead. TopK
+// does not modify the data.
+//
+// Elements which tie keep their order in the data, so the
+// result is always the first k of a stable sort, and
+// consecutive pages neither repeat nor skip an element.
+func TopK[T any](data []T, k int, lt Less[T]) []T {
+	if k <= 0 {
+		return []T{}
+	}
+	if k > len(data)/2 {
+		all := append([]T{}, data...)
+		SortStableFunc(all, lt)
+		if k > len(all) {
+			k = len(all)
+		}
+		return all[:k]
+	}
+
+	// h is a heap with the element sorted last at the top,
+	// so it is the one replaced by a better element. Ties
+	// are broken by the index of the element in the data
+	h := &heap[indexed[T]]{arr: make([]indexed[T], k), lt: byIndex(lt)}
+	for i := range h.arr {
+		h.arr[i] = indexed[T]{data[i], i}
+	}
+	h.heapify()
+	for i := k; i < len(data); i++ {
+		// an element tying with the top comes after it in
+		// the data, so only a better element replaces it
+		if lt(data[i], h.arr[0].v) {
+			h.arr[0] = indexed[T]{data[i], i}
+			h.down(0, k)
+		}
+	}
+	h.sortDown()
+
+	top := make([]T, k)
+	for i, e := range h.arr {
+		top[i] = e.v
+	}
+	return top
+}
+
+// indexed is an element of the data with its index.
+type indexed[T any] struct {
+	v T
+	i int
+}
+
+// byIndex returns a Less which sorts indexed elements by
+// lt, breaking ties by their index.
+func byIndex[T any](lt Less[T]) Less[indexed[T]] {
+	return func(a, b indexed[T]) bool {
+		if lt(a.v, b.v) {
+			return true
+		}
+		if lt(b.v, a.v) {
+			return false
+		}
+		return a.i < b.i
+	}
+}
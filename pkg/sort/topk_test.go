@@ -0,0 +1,112 @@
+package sort
+
+import (
+	"ProjectGoLive/pkg/models"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func Test_TopK(t *testing.T) {
+	rand.Seed(time.Now().UnixNano())
+
+	for sortBy, mode := range models.SortBy {
+		// the full sort is the reference
+		want := append([]*models.Product{}, list2...)
+		NewMergeSort(want, sortBy).MergeSort()
+		lt := ProductLess(sortBy)
+
+		for _, k := range []int{0, 1, 5, len(list2) / 2, len(list2) - 1, len(list2), len(list2) + 3} {
+			t.Run(fmt.Sprintf("%s/%d", mode, k), func(t *testing.T) {
+				data := append([]*models.Product{}, list2...)
+				rand.Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+				before := append([]*models.Product{}, data...)
+
+				got := TopKProducts(data, k, sortBy)
+
+				n := k
+				if n > len(want) {
+					n = len(want)
+				}
+				if len(got) != n {
+					t.Fatalf("TopK returned %v products, want %v", len(got), n)
+				}
+				for i := range got {
+					if lt(got[i], want[i]) || lt(want[i], got[i]) {
+						t.Errorf("sort didn't sort: got %v, want %v at index %v", got[i].ProductID, want[i].ProductID, i)
+					}
+				}
+				for i := range data {
+					if data[i] != before[i] {
+						t.Fatalf("TopK modified the data at index %v", i)
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_TopK_Pages(t *testing.T) {
+	// copies of the sample products tie on every key
+	data := []*models.Product{}
+	for j := 0; j < 5; j++ {
+		for _, p := range list2 {
+			c := *p
+			data = append(data, &c)
+		}
+	}
+	rand.Seed(time.Now().UnixNano())
+	rand.Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+
+	specs := map[string]Spec{"price only": MustParseSpec("price")}
+	for _, mode := range models.SortBy {
+		specs[mode] = Presets[mode]
+	}
+
+	const perPage = 7
+	for name, spec := range specs {
+		t.Run(name, func(t *testing.T) {
+			lt := spec.Less()
+			want := append([]*models.Product{}, data...)
+			SortStableFunc(want, lt)
+
+			// join the pages as the handlers select them
+			got := []*models.Product{}
+			for first := 0; first < len(data); first += perPage {
+				last := first + perPage
+				if last > len(data) {
+					last = len(data)
+				}
+				got = append(got, TopK(data, last, lt)[first:last]...)
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("pages hold %v products, want %v", len(got), len(want))
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf("pages differ from a stable sort at index %v", i)
+				}
+			}
+		})
+	}
+}
+
+func Benchmark_TopK(b *testing.B) {
+	// create a long list of products
+	list := []*models.Product{}
+	for j := 0; j < 500; j++ {
+		list = append(list, list2...)
+	}
+
+	for i := 0; i < b.N; i++ {
+		// shuffle the list
+		b.StopTimer()
+		rand.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+		b.StartTimer()
+
+		// select the first page
+		TopKProducts(list, 20, 2)
+	}
+}
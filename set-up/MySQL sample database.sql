@@ -33,13 +33,19 @@ CREATE TABLE `Orders` (
   `Status` int NOT NULL,
   `Created` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `Modified` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `VariantID` int NOT NULL DEFAULT '0',
+  `Price` float DEFAULT NULL,
+  `PromotionID` int DEFAULT NULL,
+  `Claimed` int NOT NULL DEFAULT '0',
   PRIMARY KEY (`OrderID`),
   KEY `UserID` (`UserID`),
   KEY `ProductID` (`ProductID`),
   KEY `SellerID` (`SellerID`),
+  KEY `PromotionID` (`PromotionID`),
   CONSTRAINT `orders_ibfk_1` FOREIGN KEY (`UserID`) REFERENCES `User` (`UserID`),
   CONSTRAINT `orders_ibfk_2` FOREIGN KEY (`ProductID`) REFERENCES `Product` (`ProductID`),
-  CONSTRAINT `orders_ibfk_3` FOREIGN KEY (`SellerID`) REFERENCES `User` (`UserID`)
+  CONSTRAINT `orders_ibfk_3` FOREIGN KEY (`SellerID`) REFERENCES `User` (`UserID`),
+  CONSTRAINT `orders_ibfk_4` FOREIGN KEY (`PromotionID`) REFERENCES `Promotion` (`PromotionID`) ON DELETE SET NULL
 ) ENGINE=InnoDB AUTO_INCREMENT=32 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
@@ -49,7 +55,7 @@ CREATE TABLE `Orders` (
 
 LOCK TABLES `Orders` WRITE;
 /*!40000 ALTER TABLE `Orders` DISABLE KEYS */;
-INSERT INTO `Orders` VALUES (19,'ongryan123',36,1,'ahmadmuhammad',0,'2021-05-13 04:37:25','2021-05-13 04:37:25'),(27,'ongryan123',38,6,'leematthew',1,'2021-05-13 12:33:46','2021-05-13 12:33:46'),(28,'ongryan123',52,1,'leematthew',2,'2021-05-13 12:33:46','2021-05-13 12:33:46'),(29,'ongryan123',39,5,'leematthew',1,'2021-05-13 13:12:47','2021-05-13 13:12:47'),(30,'ongryan123',39,5,'leematthew',2,'2021-05-14 02:12:24','2021-05-14 02:12:24'),(31,'ongryan123',52,3,'leematthew',1,'2021-05-14 02:12:24','2021-05-14 02:12:24');
+INSERT INTO `Orders` VALUES (19,'ongryan123',36,1,'ahmadmuhammad',0,'2021-05-13 04:37:25','2021-05-13 04:37:25',0,NULL,NULL,0),(27,'ongryan123',38,6,'leematthew',1,'2021-05-13 12:33:46','2021-05-13 12:33:46',0,NULL,NULL,0),(28,'ongryan123',52,1,'leematthew',2,'2021-05-13 12:33:46','2021-05-13 12:33:46',0,NULL,NULL,0),(29,'ongryan123',39,5,'leematthew',1,'2021-05-13 13:12:47','2021-05-13 13:12:47',0,NULL,NULL,0),(30,'ongryan123',39,5,'leematthew',2,'2021-05-14 02:12:24','2021-05-14 02:12:24',0,NULL,NULL,0),(31,'ongryan123',52,3,'leematthew',1,'2021-05-14 02:12:24','2021-05-14 02:12:24',0,NULL,NULL,0);
 /*!40000 ALTER TABLE `Orders` ENABLE KEYS */;
 UNLOCK TABLES;
 
@@ -93,6 +99,163 @@ INSERT INTO `Product` VALUES (32,'Grass Fed Ribeye Steak (300g)','Our Premium An
 /*!40000 ALTER TABLE `Product` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `ProductImage`
+--
+
+DROP TABLE IF EXISTS `ProductImage`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `ProductImage` (
+  `ImageID` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `ProductID` int NOT NULL,
+  `Position` int NOT NULL DEFAULT '0',
+  `Created` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`ImageID`),
+  KEY `ProductID` (`ProductID`,`Position`),
+  CONSTRAINT `productimage_ibfk_1` FOREIGN KEY (`ProductID`) REFERENCES `Product` (`ProductID`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `ProductImage`
+--
+
+LOCK TABLES `ProductImage` WRITE;
+/*!40000 ALTER TABLE `ProductImage` DISABLE KEYS */;
+/*!40000 ALTER TABLE `ProductImage` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `ProductVariant`
+--
+
+DROP TABLE IF EXISTS `ProductVariant`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `ProductVariant` (
+  `VariantID` int NOT NULL AUTO_INCREMENT,
+  `ProductID` int NOT NULL,
+  `Name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `Price` float NOT NULL,
+  `DiscountID` int NOT NULL DEFAULT '0',
+  `Inventory` int NOT NULL,
+  `UnitSold` int NOT NULL DEFAULT '0',
+  `Created` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `Modified` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`VariantID`),
+  UNIQUE KEY `ProductName` (`ProductID`,`Name`),
+  CONSTRAINT `productvariant_ibfk_1` FOREIGN KEY (`ProductID`) REFERENCES `Product` (`ProductID`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `ProductVariant`
+--
+
+LOCK TABLES `ProductVariant` WRITE;
+/*!40000 ALTER TABLE `ProductVariant` DISABLE KEYS */;
+/*!40000 ALTER TABLE `ProductVariant` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `Promotion`
+--
+
+DROP TABLE IF EXISTS `Promotion`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `Promotion` (
+  `PromotionID` int NOT NULL AUTO_INCREMENT,
+  `ProductID` int NOT NULL,
+  `Name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `Kind` tinyint NOT NULL DEFAULT '0',
+  `Value` float NOT NULL,
+  `Starts` datetime NOT NULL,
+  `Ends` datetime NOT NULL,
+  `StockCap` int NOT NULL DEFAULT '0',
+  `Sold` int NOT NULL DEFAULT '0',
+  `Created` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`PromotionID`),
+  KEY `ProductEnds` (`ProductID`,`Ends`),
+  CONSTRAINT `promotion_ibfk_1` FOREIGN KEY (`ProductID`) REFERENCES `Product` (`ProductID`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `Promotion`
+--
+
+LOCK TABLES `Promotion` WRITE;
+/*!40000 ALTER TABLE `Promotion` DISABLE KEYS */;
+/*!40000 ALTER TABLE `Promotion` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `Review`
+--
+
+DROP TABLE IF EXISTS `Review`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `Review` (
+  `ReviewID` int NOT NULL AUTO_INCREMENT,
+  `OrderID` int NOT NULL,
+  `ProductID` int NOT NULL,
+  `UserID` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `Rating` tinyint NOT NULL,
+  `Text` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `Reply` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,
+  `Replied` timestamp NULL DEFAULT NULL,
+  `Created` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`ReviewID`),
+  UNIQUE KEY `OrderID` (`OrderID`),
+  KEY `ProductID` (`ProductID`,`Created`),
+  KEY `UserID` (`UserID`),
+  CONSTRAINT `review_ibfk_1` FOREIGN KEY (`OrderID`) REFERENCES `Orders` (`OrderID`),
+  CONSTRAINT `review_ibfk_2` FOREIGN KEY (`ProductID`) REFERENCES `Product` (`ProductID`),
+  CONSTRAINT `review_ibfk_3` FOREIGN KEY (`UserID`) REFERENCES `User` (`UserID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `Review`
+--
+
+LOCK TABLES `Review` WRITE;
+/*!40000 ALTER TABLE `Review` DISABLE KEYS */;
+/*!40000 ALTER TABLE `Review` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `SearchQuery`
+--
+
+DROP TABLE IF EXISTS `SearchQuery`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `SearchQuery` (
+  `QueryID` int NOT NULL AUTO_INCREMENT,
+  `Query` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
+  `Results` int NOT NULL,
+  `Clicked` tinyint(1) NOT NULL DEFAULT '0',
+  `ProductID` int DEFAULT NULL,
+  `Created` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`QueryID`),
+  KEY `Query` (`Query`),
+  KEY `Created` (`Created`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `SearchQuery`
+--
+
+LOCK TABLES `SearchQuery` WRITE;
+/*!40000 ALTER TABLE `SearchQuery` DISABLE KEYS */;
+/*!40000 ALTER TABLE `SearchQuery` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `ShoppingCart`
 --
@@ -103,9 +266,10 @@ DROP TABLE IF EXISTS `ShoppingCart`;
 CREATE TABLE `ShoppingCart` (
   `UserID` varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,
   `ProductID` int NOT NULL,
+  `VariantID` int NOT NULL DEFAULT '0',
   `Qty` int NOT NULL,
   `Modified` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
-  PRIMARY KEY (`UserID`,`ProductID`),
+  PRIMARY KEY (`UserID`,`ProductID`,`VariantID`),
   KEY `UserID` (`UserID`),
   KEY `ProductID` (`ProductID`),
   CONSTRAINT `shoppingcart_ibfk_1` FOREIGN KEY (`UserID`) REFERENCES `User` (`UserID`),
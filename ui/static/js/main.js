@@ -5,4 +5,50 @@ for (var i = 0; i < navLinks.length; i++) {
 		link.classList.add("live");
 		break;
 	}
+}
+
+// suggest completions for the search bar while the user types
+var searchBar = document.getElementById("searchbar");
+var suggestions = document.getElementById("suggestions");
+var suggestTimer;
+if (searchBar && suggestions) {
+	searchBar.addEventListener("input", function () {
+		clearTimeout(suggestTimer);
+		var text = searchBar.value.trim();
+		if (text.length < 2) {
+			suggestions.innerHTML = "";
+			return;
+		}
+		suggestTimer = setTimeout(function () {
+			fetch("/product/suggest?text=" + encodeURIComponent(text))
+				.then(function (response) { return response.json(); })
+				.then(function (items) {
+					suggestions.innerHTML = "";
+					for (var i = 0; i < items.length; i++) {
+						var option = document.createElement("option");
+						option.value = items[i].text;
+						suggestions.appendChild(option);
+					}
+				});
+		}, 150);
+	});
+}
+
+// count down the time left in flash sales every second
+var countdowns = document.querySelectorAll(".countdown[data-ends]");
+function pad(n) {
+	return n < 10 ? "0" + n : "" + n;
+}
+function tick() {
+	var now = Date.now() / 1000;
+	for (var i = 0; i < countdowns.length; i++) {
+		var left = Math.max(0, Math.floor(countdowns[i].getAttribute("data-ends") - now));
+		var days = Math.floor(left / 86400);
+		var text = pad(Math.floor(left / 3600) % 24) + ":" + pad(Math.floor(left / 60) % 60) + ":" + pad(left % 60);
+		countdowns[i].textContent = left > 0 ? "Ends in " + (days > 0 ? days + "d " : "") + text : "Ended";
+	}
+}
+if (countdowns.length > 0) {
+	tick();
+	setInterval(tick, 1000);
 }
\ No newline at end of file